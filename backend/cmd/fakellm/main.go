@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm/fakellm"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8089", "endereço em que o servidor escuta")
+	scenario := flag.String("scenario", fakellm.ScenarioValid, "cenário usado quando o modelo da requisição não é um cenário")
+	flag.Parse()
+
+	server := fakellm.New()
+	if err := server.SetDefaultScenario(*scenario); err != nil {
+		log.Fatal(err)
+	}
+
+	names := make([]string, 0, len(fakellm.Scenarios))
+	for name := range fakellm.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("Fake LLM escutando em http://%s/v1 (cenário padrão: %s)\n", *addr, *scenario)
+	fmt.Printf("Aponte o backend para ele com LLM_PROVIDER=llamacpp LLM_BASE_URL=http://%s/v1\n", *addr)
+	fmt.Printf("e escolha o cenário por requisição com LLM_MODEL=<cenário>. Cenários: %v\n", names)
+
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"strconv"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/api"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/database"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/handler"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/jobs"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/storage"
 	"github.com/joho/godotenv"
 )
 
@@ -23,19 +29,80 @@ func main() {
 	flashcardRepo := repository.NewFlashcardRepository(database.DB)
 	flashcardSetRepo := repository.NewFlashcardSetRepository(database.DB)
 	userRepo := repository.NewUserRepository(database.DB)
+	reviewRepo := repository.NewReviewRepository(database.DB)
+	settingsRepo := repository.NewSettingsRepository(database.DB)
+	studySessionRepo := repository.NewStudySessionRepository(database.DB)
+	jobRepo := repository.NewJobRepository(database.DB)
+	sourceRepo := repository.NewSourceRepository(database.DB)
+	transactor := repository.NewTransactor(database.DB)
 
 	// 3. Cria os serviços, injetando os repositórios correspondentes.
-	flashcardService := services.NewFlashcardService(flashcardRepo, flashcardSetRepo)
+	flashcardService := services.NewFlashcardService(flashcardRepo, flashcardSetRepo, transactor)
 	flashcardSetService := services.NewFlashcardSetService(flashcardSetRepo)
 	userService := services.NewUserService(userRepo)
+	settingsService := services.NewSettingsService(settingsRepo, reviewRepo)
+	reviewService := services.NewReviewService(reviewRepo, flashcardRepo, flashcardSetRepo, settingsService, transactor)
+	jobService := services.NewJobService(jobRepo)
+	blobConfig, err := storage.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("Configuração do blob store inválida: ", err)
+	}
+	blobStore, err := storage.New(blobConfig)
+	if err != nil {
+		log.Fatal("Erro ao abrir o blob store: ", err)
+	}
+	sourceService := services.NewSourceService(sourceRepo, blobStore)
+	studyService := services.NewStudyService(reviewRepo, studySessionRepo, flashcardSetRepo, flashcardRepo, reviewService, settingsService)
+
+	// 4. Configura o provider de LLM usado na geração dos flashcards
+	llmConfig, err := llm.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("Configuração de LLM inválida: ", err)
+	}
+	llmClient := llm.NewClient(llmConfig)
+	var chatter llm.Chatter = llmClient
+	log.Printf("LLM configurado: %s (%s)", llmConfig.Model, llmConfig.BaseURL)
+	if fallbackConfig, ok := llm.FallbackConfigFromEnv(llmConfig); ok {
+		chatter = llm.WithFallback(llmClient, llm.NewClient(fallbackConfig))
+		log.Printf("LLM reserva configurado: %s (%s)", fallbackConfig.Model, fallbackConfig.BaseURL)
+	}
+	var vision llm.Chatter
+	if visionConfig, ok := llm.VisionConfigFromEnv(llmConfig); ok {
+		vision = llm.NewClient(visionConfig)
+		log.Printf("LLM de visão configurado: %s (%s)", visionConfig.Model, visionConfig.BaseURL)
+	}
+	generator := llm.NewGenerator(chatter, vision)
 
-	// 4. Cria os handlers, injetando os serviços que eles utilizarão.
-	flashcardHandler := handler.NewFlashcardHandler(flashcardService, flashcardSetService, userService)
+	// 5. Cria os handlers, injetando os serviços que eles utilizarão.
+	flashcardHandler := handler.NewFlashcardHandler(flashcardService, flashcardSetService, userService, jobService, sourceService, generator)
 	flashcardSetHandler := handler.NewFlashcardSetHandler(flashcardService, flashcardSetService, userService)
+	reviewHandler := handler.NewReviewHandler(reviewService, userService)
+	settingsHandler := handler.NewSettingsHandler(settingsService, userService)
+	studyHandler := handler.NewStudyHandler(studyService, userService)
+	jobHandler := handler.NewJobHandler(jobService)
+	sourceHandler := handler.NewSourceHandler(sourceService, userService)
 
-	// 5. Setup Router
-	router := api.SetupRouter(flashcardHandler, flashcardSetHandler)
+	// 6. Setup Router
+	router := api.SetupRouter(flashcardHandler, flashcardSetHandler, reviewHandler, settingsHandler, studyHandler, jobHandler, sourceHandler)
+
+	// 7. Sobe os workers que processam os jobs de geração em segundo plano
+	jobsConfig := jobs.DefaultConfig
+	if n, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && n > 0 {
+		jobsConfig.Workers = n
+	}
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	jobPool := jobs.NewPool(jobsConfig, jobRepo, generator, flashcardService, transactor)
+	jobPool.Start(workersCtx)
 
-	// 6. Inicia o servidor
+	// 8. Inicia o servidor
 	api.RunServer(router)
+
+	// Jobs em andamento voltam para a fila e são retomados no próximo start
+	stopWorkers()
+	jobPool.Wait()
+
+	// 9. Libera as conexões do pool depois que as requisições em andamento terminaram
+	if err := database.DB.Close(); err != nil {
+		log.Printf("Erro ao fechar o banco: %v", err)
+	}
 }
\ No newline at end of file
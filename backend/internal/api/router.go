@@ -1,8 +1,15 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/handler"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/middleware"
@@ -11,13 +18,13 @@ import (
 )
 
 // SetupRouter initializes the Gin router and maps the API routes.
-func SetupRouter(flashcardHandler *handler.FlashcardHandler, flashcardSetHandler *handler.FlashcardSetHandler) *gin.Engine {
+func SetupRouter(flashcardHandler *handler.FlashcardHandler, flashcardSetHandler *handler.FlashcardSetHandler, reviewHandler *handler.ReviewHandler, settingsHandler *handler.SettingsHandler, studyHandler *handler.StudyHandler, jobHandler *handler.JobHandler, sourceHandler *handler.SourceHandler) *gin.Engine {
         router := gin.Default()
 
         // Configure CORS
         router.Use(cors.New(cors.Config{
                 AllowOrigins:     []string{"http://localhost:5173", "http://localhost:5000", "*"},
-                AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+                AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
                 AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
                 ExposeHeaders:    []string{"Content-Length"},
                 AllowCredentials: true,
@@ -29,12 +36,49 @@ func SetupRouter(flashcardHandler *handler.FlashcardHandler, flashcardSetHandler
                 apiV1.GET("flashcardsets/:set_id/flashcards", flashcardHandler.GetFlashcardsBySetID)
                 apiV1.GET("flashcardsets/:set_id", flashcardSetHandler.GetFlashcardSetByID)
 
+                apiV1.POST("/flashcardsets", flashcardSetHandler.CreateFlashcardSet)
+                apiV1.PUT("/flashcardsets/:set_id", flashcardSetHandler.ReplaceFlashcardSet)
+                apiV1.PATCH("/flashcardsets/:set_id", flashcardSetHandler.PatchFlashcardSet)
+                apiV1.DELETE("/flashcardsets/:set_id", flashcardSetHandler.DeleteFlashcardSet)
+                apiV1.POST("/flashcardsets/:set_id/flashcards", flashcardHandler.CreateFlashcard)
+                apiV1.PUT("/flashcardsets/:set_id/order", flashcardHandler.ReorderFlashcards)
+                apiV1.POST("/flashcardsets/:set_id/flashcards/move", flashcardHandler.MoveFlashcards)
+                apiV1.POST("/flashcardsets/:set_id/flashcards/copy", flashcardHandler.CopyFlashcards)
+
+                apiV1.PUT("/flashcards/:id", flashcardHandler.ReplaceFlashcard)
+                apiV1.PATCH("/flashcards/:id", flashcardHandler.PatchFlashcard)
+                apiV1.DELETE("/flashcards/:id", flashcardHandler.DeleteFlashcard)
+
                 apiV1.GET("/users/:user_id/flashcardsets", flashcardSetHandler.GetFlashcardSets)
                 apiV1.GET("/users/:user_id/flashcards-topic", flashcardHandler.GetFlashcardsByTopic)
                 apiV1.GET("/users/:user_id/flashcards", flashcardHandler.GetAllUserFlashcards)
 
+                // Same endpoints for the authenticated user, without sending the user ID
+                apiV1.GET("/me/flashcardsets", flashcardSetHandler.GetFlashcardSets)
+                apiV1.GET("/me/flashcards-topic", flashcardHandler.GetFlashcardsByTopic)
+                apiV1.GET("/me/flashcards", flashcardHandler.GetAllUserFlashcards)
+
                 apiV1.POST("/flashcards/generate", flashcardHandler.GenerateFlashcards)
                 apiV1.POST("/flashcards/generate-from-summary", flashcardHandler.GenerateFlashcardsFromSummary)
+                apiV1.POST("/flashcards/generate/stream", flashcardHandler.GenerateFlashcardsStream)
+                apiV1.POST("/flashcards/generate-from-summary/stream", flashcardHandler.GenerateFlashcardsFromSummaryStream)
+                apiV1.POST("/flashcards/:id/review", reviewHandler.ReviewFlashcard)
+
+                apiV1.POST("/sources", sourceHandler.UploadSource)
+                apiV1.GET("/sources/:id", sourceHandler.GetSource)
+
+                apiV1.GET("/jobs/:id", jobHandler.GetJob)
+                apiV1.DELETE("/jobs/:id", jobHandler.CancelJob)
+
+                apiV1.GET("/me/settings", settingsHandler.GetSettings)
+                apiV1.PUT("/me/settings", settingsHandler.UpdateSettings)
+                apiV1.POST("/me/settings/fsrs/optimize", settingsHandler.OptimizeFSRS)
+                apiV1.GET("/me/study/due", studyHandler.GetDueQueue)
+                apiV1.POST("/me/study/sessions", studyHandler.StartSession)
+                apiV1.GET("/me/study/sessions/:session_id", studyHandler.GetSession)
+                apiV1.POST("/me/study/sessions/:session_id/answers", studyHandler.RecordAnswer)
+                apiV1.POST("/me/study/sessions/:session_id/finish", studyHandler.FinishSession)
+
                 // Add OPTIONS route for CORS preflight
                 apiV1.OPTIONS("/flashcards/generate", func(c *gin.Context) {
                         c.Status(200)
@@ -42,6 +86,15 @@ func SetupRouter(flashcardHandler *handler.FlashcardHandler, flashcardSetHandler
                 apiV1.OPTIONS("/flashcards/generate-from-summary", func(c *gin.Context) {
                         c.Status(200)
                 })
+                apiV1.OPTIONS("/flashcards/generate/stream", func(c *gin.Context) {
+                        c.Status(200)
+                })
+                apiV1.OPTIONS("/flashcards/generate-from-summary/stream", func(c *gin.Context) {
+                        c.Status(200)
+                })
+                apiV1.OPTIONS("/sources", func(c *gin.Context) {
+                        c.Status(200)
+                })
         }
 
     
@@ -49,14 +102,58 @@ func SetupRouter(flashcardHandler *handler.FlashcardHandler, flashcardSetHandler
         return router
 }
 
-// RunServer starts the Gin server with the port specified in the environment.
+// shutdownGracePeriod é quanto o servidor espera as requisições em andamento terminarem
+// depois de um SIGINT/SIGTERM antes de cancelar os contextos delas.
+const shutdownGracePeriod = 30 * time.Second
+
+// RunServer starts the Gin server with the port specified in the environment and blocks until
+// SIGINT/SIGTERM. Every request context derives from a base context that is cancelled when the
+// grace period runs out, so in-flight DB queries and LLM calls stop instead of holding the process.
 func RunServer(router *gin.Engine) {
         port := os.Getenv("PORT")
         if port == "" {
                 port = "8080"
         }
-        log.Printf("Servidor rodando na porta %s", port)
-        router.Run(":" + port)
+
+        baseCtx, cancelRequests := context.WithCancel(context.Background())
+        defer cancelRequests()
+
+        srv := &http.Server{
+                Addr:              ":" + port,
+                Handler:           router,
+                ReadHeaderTimeout: 10 * time.Second,
+                BaseContext:       func(net.Listener) context.Context { return baseCtx },
+        }
+
+        stop, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+        defer stopSignals()
+
+        serverErr := make(chan error, 1)
+        go func() {
+                log.Printf("Servidor rodando na porta %s", port)
+                serverErr <- srv.ListenAndServe()
+        }()
+
+        select {
+        case err := <-serverErr:
+                if !errors.Is(err, http.ErrServerClosed) {
+                        log.Fatal("Erro ao iniciar o servidor: ", err)
+                }
+                return
+        case <-stop.Done():
+        }
+
+        log.Printf("Encerrando o servidor (aguardando até %s pelas requisições em andamento)", shutdownGracePeriod)
+        shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+        defer cancel()
+
+        if err := srv.Shutdown(shutdownCtx); err != nil {
+                // Prazo esgotado: cancela o que ainda está rodando e fecha as conexões
+                log.Printf("Requisições ainda em andamento após %s, cancelando: %v", shutdownGracePeriod, err)
+                cancelRequests()
+                srv.Close()
+        }
+        log.Println("Servidor encerrado")
 }
 
 // GET ALL FLASHCARDS SETS OF A USER, AND ALSO GET ALL THE FLASHCARDS FROM EACH FLASHCARD SET ON THIS RESPONSE
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
@@ -29,7 +30,9 @@ func InitDB() {
 	DB.SetConnMaxLifetime(5 * time.Minute)  // Maximum lifetime of a connection
 	DB.SetConnMaxIdleTime(2 * time.Minute)  // Maximum idle time for a connection
 	
-	if err := DB.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := DB.PingContext(ctx); err != nil {
 		log.Fatal("Banco não respondeu:", err)
 	}
 	
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// authenticatedUserID reads the user set by middleware.SupabaseAuth (the JWT "sub" claim).
+// It writes the error response itself and returns false when the request must stop.
+func authenticatedUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// targetUserID resolves whose data the request reads. Routes under /users/:user_id must name
+// the authenticated user (403 otherwise); /me routes have no path parameter and use it directly.
+func targetUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	userIDStr := c.Param("user_id")
+	if userIDStr == "" {
+		return userID, true
+	}
+
+	pathUserID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	if err := services.AuthorizeUser(userID, pathUserID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// userFromContext reads the authenticated user and makes sure it exists in our database,
+// which is required before writing rows that reference it.
+func userFromContext(c *gin.Context, userService services.UserService) (uuid.UUID, bool) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	email := c.GetString("userEmail") // Optional, might be empty
+
+	if _, err := userService.EnsureUserExists(c.Request.Context(), userID, email); err != nil {
+		log.Printf("Error ensuring user exists: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify user"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// pageFromQuery reads ?limit, ?cursor, ?sort and ?order for a list endpoint. sorts are the values
+// accepted by that endpoint; a cursor is only valid with the sort and order that produced it.
+// It writes a 400 itself and returns false when the parameters are invalid.
+func pageFromQuery(c *gin.Context, sorts []string, defaultSort string, defaultDesc bool) (model.PageRequest, bool) {
+	page := model.PageRequest{Limit: model.DefaultPageLimit, Sort: defaultSort, Desc: defaultDesc}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > model.MaxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", model.MaxPageLimit)})
+			return model.PageRequest{}, false
+		}
+		page.Limit = limit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		if !slices.Contains(sorts, sort) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of: " + strings.Join(sorts, ", ")})
+			return model.PageRequest{}, false
+		}
+		page.Sort = sort
+	}
+
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		page.Desc = false
+	case "desc":
+		page.Desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return model.PageRequest{}, false
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := model.DecodeCursor(cursorStr)
+		if err != nil || cursor.Sort != page.Sort || cursor.Desc != page.Desc {
+			c.JSON(http.StatusBadRequest, gin.H{"error": model.ErrInvalidCursor.Error()})
+			return model.PageRequest{}, false
+		}
+		page.Cursor = &cursor
+	}
+
+	return page, true
+}
+
+// setFilterFromQuery reads the filters of the set listings (?difficulty, aliases accepted).
+// It writes a 400 itself and returns false when a filter is invalid.
+func setFilterFromQuery(c *gin.Context) (model.FlashcardSetFilter, bool) {
+	var filter model.FlashcardSetFilter
+
+	if value := c.Query("difficulty"); value != "" {
+		difficulty, err := model.ParseDifficulty(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return model.FlashcardSetFilter{}, false
+		}
+		filter.Difficulty = &difficulty
+	}
+
+	return filter, true
+}
+
+// nextCursor renders the cursor returned by the service for the response envelope: null on the last page.
+func nextCursor(next string) any {
+	if next == "" {
+		return nil
+	}
+	return next
+}
+
+// respondServiceError maps the errors returned by the service layer to HTTP responses.
+// Unexpected errors are logged and answered with a generic 500 message.
+func respondServiceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrCardLimitExceeded):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidFlashcards):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSessionClosed), errors.Is(err, services.ErrJobFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
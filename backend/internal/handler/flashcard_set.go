@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -34,16 +35,23 @@ func (h *FlashcardSetHandler) GetFlashcards(c *gin.Context) {
 func (h *FlashcardSetHandler) GetFlashcardSets(c *gin.Context) {
 	// Obter todos os flashcard sets do usuário com contagem de flashcards
 	
-	userIDStr := c.Param("user_id")
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	page, ok := pageFromQuery(c, setSorts, model.SortCreatedAt, true)
+	if !ok {
+		return
+	}
+
+	filter, ok := setFilterFromQuery(c)
+	if !ok {
 		return
 	}
 
-	// Use the flashcard service to get sets with flashcard counts
-	flashcardSets, err := h.flashcardService.GetAllUserFlashcards(context.Background(), userID)
+	// Totais calculados numa única query agregada, sem carregar os cards
+	summaries, next, err := h.flashcardSetService.GetSummaries(c.Request.Context(), userID, time.Now(), filter, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flashcard sets"})
 		log.Println("Erro ao obter os flashcard sets:", err)
@@ -52,27 +60,35 @@ func (h *FlashcardSetHandler) GetFlashcardSets(c *gin.Context) {
 
 	// Transform the response to include flashcard counts
 	type FlashcardSetWithCount struct {
-		ID             string `json:"id"`
-		UserID         string `json:"user_id"`
-		Topic          string `json:"topic"`
-		CreatedAt      string `json:"created_at"`
-		UpdatedAt      string `json:"updated_at"`
-		FlashcardCount int    `json:"flashcard_count"`
+		ID             string     `json:"id"`
+		UserID         string     `json:"user_id"`
+		Topic          string     `json:"topic"`
+		Difficulty     *model.Difficulty `json:"difficulty"`
+		CreatedAt      string     `json:"created_at"`
+		UpdatedAt      string     `json:"updated_at"`
+		FlashcardCount int        `json:"flashcard_count"`
+		DueCount       int        `json:"due_count"`
+		NewCount       int        `json:"new_count"`
+		LastStudiedAt  *time.Time `json:"last_studied_at"`
 	}
 
-	var response []FlashcardSetWithCount
-	for _, set := range flashcardSets {
+	response := make([]FlashcardSetWithCount, 0, len(summaries))
+	for _, set := range summaries {
 		response = append(response, FlashcardSetWithCount{
-			ID:             set.ID,
-			UserID:         set.UserID,
+			ID:             set.ID.String(),
+			UserID:         set.UserID.String(),
 			Topic:          set.Topic,
-			CreatedAt:      set.CreatedAt,
-			UpdatedAt:      set.UpdatedAt,
-			FlashcardCount: len(set.Flashcards),
+			Difficulty:     set.Difficulty,
+			CreatedAt:      set.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:      set.UpdatedAt.Format("2006-01-02 15:04:05"),
+			FlashcardCount: set.FlashcardCount,
+			DueCount:       set.DueCount,
+			NewCount:       set.NewCount,
+			LastStudiedAt:  set.LastStudiedAt,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"flashcard_sets": response})
+	c.JSON(http.StatusOK, gin.H{"flashcard_sets": response, "next_cursor": nextCursor(next)})
 }
 
 func (h *FlashcardSetHandler) GetFlashcardSetByID(c *gin.Context) {
@@ -86,18 +102,122 @@ func (h *FlashcardSetHandler) GetFlashcardSetByID(c *gin.Context) {
 		return
 	}
 	
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 	
 	log.Printf("Calling flashcard set service to get set: %s", fsetID.String())
-	flashcardSet, err := h.flashcardSetService.GetByID(ctx, fsetID)
+	flashcardSet, err := h.flashcardSetService.GetByID(ctx, userID, fsetID)
 	if err != nil {
-		log.Printf("Erro ao obter o flashcard set %s: %v", fsetID.String(), err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flashcard set"})
+		respondServiceError(c, err, "failed to fetch flashcard set")
 		return
 	}
 	
 	log.Printf("Successfully retrieved flashcard set: %s with topic: %s", fsetID.String(), flashcardSet.Topic)
 	c.JSON(http.StatusOK, gin.H{"flashcard_set": flashcardSet})
-}
\ No newline at end of file
+}
+
+// CreateFlashcardSet creates a set written by the user (optionally with its cards) without calling the LLM.
+func (h *FlashcardSetHandler) CreateFlashcardSet(c *gin.Context) {
+	var req model.FlashcardSetInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	cards := make([]model.Flashcard, 0, len(req.Flashcards))
+	for _, input := range req.Flashcards {
+		cards = append(cards, model.Flashcard{QuestionText: input.QuestionText, AnswerText: input.AnswerText})
+	}
+
+	set := model.FlashcardSet{UserID: userID, Topic: req.Topic}
+	if req.Difficulty != "" {
+		set.Difficulty = &req.Difficulty
+	}
+
+	set, stored, err := h.flashcardService.CreateSetWithFlashcards(ctx, set, cards)
+	if err != nil {
+		respondServiceError(c, err, "failed to create flashcard set")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"flashcard_set": set, "flashcards": stored})
+}
+
+// ReplaceFlashcardSet handles PUT: every editable field must be sent. As on creation, a missing
+// difficulty leaves the set without a level.
+func (h *FlashcardSetHandler) ReplaceFlashcardSet(c *gin.Context) {
+	var req struct {
+		Topic      string           `json:"topic" binding:"required"`
+		Difficulty model.Difficulty `json:"difficulty"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	h.updateFlashcardSet(c, model.FlashcardSetPatch{Topic: &req.Topic, Difficulty: &req.Difficulty})
+}
+
+// PatchFlashcardSet handles PATCH: only the fields sent are changed.
+func (h *FlashcardSetHandler) PatchFlashcardSet(c *gin.Context) {
+	var patch model.FlashcardSetPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	h.updateFlashcardSet(c, patch)
+}
+
+func (h *FlashcardSetHandler) updateFlashcardSet(c *gin.Context, patch model.FlashcardSetPatch) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	set, err := h.flashcardSetService.Update(c.Request.Context(), userID, setID, patch)
+	if err != nil {
+		respondServiceError(c, err, "failed to update flashcard set")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flashcard_set": set})
+}
+
+// DeleteFlashcardSet removes the set and all of its cards.
+func (h *FlashcardSetHandler) DeleteFlashcardSet(c *gin.Context) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.flashcardSetService.Delete(c.Request.Context(), userID, setID); err != nil {
+		respondServiceError(c, err, "failed to delete flashcard set")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
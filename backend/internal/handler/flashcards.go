@@ -3,11 +3,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
-	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/deepseek"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/ingestion"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
 	"github.com/gin-gonic/gin"
@@ -18,16 +20,29 @@ type FlashcardHandler struct {
 	flashcardService services.FlashcardService
 	flashcardSetService services.FlashcardSetService
 	userService services.UserService
+	jobService services.JobService
+	sourceService services.SourceService
+	generator llm.Generator
 }
 
-func NewFlashcardHandler(fs services.FlashcardService, fss services.FlashcardSetService, us services.UserService) *FlashcardHandler {
+func NewFlashcardHandler(fs services.FlashcardService, fss services.FlashcardSetService, us services.UserService, js services.JobService, ss services.SourceService, gen llm.Generator) *FlashcardHandler {
 	return &FlashcardHandler{
 		flashcardService: fs, 
 		flashcardSetService: fss,
 		userService: us,
+		jobService: js,
+		sourceService: ss,
+		generator: gen,
 	}
 }
 
+// Ordenações aceitas em ?sort= nas listagens de cards e de sets.
+var (
+	setFlashcardSorts   = []string{model.SortCardOrder, model.SortCreatedAt, model.SortUpdatedAt}
+	topicFlashcardSorts = []string{model.SortCardOrder, model.SortCreatedAt, model.SortUpdatedAt, model.SortTopic}
+	setSorts            = []string{model.SortCreatedAt, model.SortUpdatedAt, model.SortTopic}
+)
+
 // FlashcardResponse represents the format expected by the frontend
 type FlashcardResponse struct {
 	ID    string `json:"id"`
@@ -35,8 +50,9 @@ type FlashcardResponse struct {
 	Back  string `json:"back"`
 }
 
-// GenerateFlashcardsHandler handles POST requests to generate flashcards using the DeepSeek API.
-// It expects a JSON payload with a "prompt" field and user_id.
+// GenerateFlashcardsHandler handles POST requests to generate flashcards using the configured LLM.
+// It expects a JSON payload with a "prompt" field. Generation can take minutes, so the request
+// only enqueues a job and answers 202 with its ID; poll GET /jobs/:id for status and the set ID.
 func (h *FlashcardHandler) GenerateFlashcards(c *gin.Context) {
 	var promptReq model.PromptRequest
 
@@ -46,151 +62,248 @@ func (h *FlashcardHandler) GenerateFlashcards(c *gin.Context) {
 		return
 	}
 
-	// Get user info from context (set by auth middleware)
-	userIDStr, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	// Get user info from context (set by auth middleware) and ensure it exists in our database
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
 		return
 	}
 
-	userEmail, _ := c.Get("userEmail") // Optional, might be empty
+	if !h.resolveOptions(c, userID, &promptReq.GenerationOptions) {
+		return
+	}
 
-	userID, err := uuid.Parse(userIDStr.(string))
+	job, err := h.jobService.EnqueuePrompt(c.Request.Context(), userID, promptReq)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondServiceError(c, err, "failed to enqueue generation job")
 		return
 	}
 
-	ctx := context.Background()
+	log.Printf("Job de geração %s enfileirado para usuário %s", job.ID.String(), userID.String())
 
-	// Ensure user exists in our database
-	email := ""
-	if userEmail != nil {
-		email = userEmail.(string)
+	statusURL := "/api/v1/jobs/" + job.ID.String()
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{"job_id": job.ID, "status": job.Status, "status_url": statusURL})
+}
+
+// GenerateFlashcardsFromSummary handles POST requests to generate flashcards from summary content.
+// It supports text, PDF, and image content types, sent inline or as the source_id of a file
+// uploaded to POST /sources.
+func (h *FlashcardHandler) GenerateFlashcardsFromSummary(c *gin.Context) {
+	var summaryReq model.SummaryRequest
+
+	// Validate the incoming JSON payload.
+	if err := c.ShouldBindJSON(&summaryReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
 	}
-	
-	_, err = h.userService.EnsureUserExists(ctx, userID, email)
-	if err != nil {
-		log.Printf("Error ensuring user exists: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify user"})
+
+	// Get user info from context (set by auth middleware) and ensure it exists in our database
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
 		return
 	}
 
-	// 1. Criar o FlashcardSet
-	set := model.FlashcardSet{
-		UserID:    userID, // Use userID from context instead of request body
-		Topic:     promptReq.Prompt, // opcional: extração simples
+	if !h.resolveOptions(c, userID, &summaryReq.GenerationOptions) {
+		return
 	}
-	setID, err := h.flashcardSetService.Create(ctx, set)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create flashcard set"})
-		log.Printf("Erro ao criar o flashcard set: %v", err)
+
+	if !h.resolveSource(c, userID, &summaryReq) {
 		return
 	}
 
-	// 2. Gerar os flashcards
-	flashcardSet, err := deepseek.GenerateFlashcards(promptReq.Prompt, promptReq.Level)
+	ctx := c.Request.Context()
+
+	topicName := summaryTopic(summaryReq)
+
+	// 1. Generate flashcards from summary content before creating anything in the database
+	flashcardSet, err := h.generator.GenerateFlashcardsFromSummary(ctx, summaryReq)
+	if ingestion.IsRejected(err) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, llm.ErrNoVisionModel) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 3. Gerar e salvar os flashcards associando ao setID
-	stored, err := h.flashcardService.GenerateAndStoreFlashcards(ctx, flashcardSet.Flashcards, setID)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
+	// 2. Create the set and store the generated flashcards in a single transaction
+	difficulty := summaryReq.Level.OrDefault()
+	set := model.FlashcardSet{
+		UserID:     userID,
+		Topic:      topicName,
+		Difficulty: &difficulty,
+	}
+	set, stored, err := h.flashcardService.CreateSetWithFlashcards(ctx, set, flashcardSet.Flashcards)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store flashcard set"})
+		log.Printf("Erro ao salvar o flashcard set: %v", err)
+		return
+	}
+	setID := set.ID
 
 	// Log the generated flashcards for debugging purposes.
-	log.Printf("Criado set ID: %s com %d flashcards para usuário %s", setID.String(), len(stored), userID.String())
+	log.Printf("Criado set ID: %s com %d flashcards do resumo para usuário %s", setID.String(), len(stored), userID.String())
 
 	// Respond with the generated flashcards.
 	c.JSON(http.StatusOK, gin.H{"flashcard_set_id": setID, "flashcards": stored})
 }
 
-// GenerateFlashcardsFromSummary handles POST requests to generate flashcards from summary content.
-// It supports text, PDF, and image content types.
-func (h *FlashcardHandler) GenerateFlashcardsFromSummary(c *gin.Context) {
-	var summaryReq model.SummaryRequest
+// resolveOptions aplica os padrões às opções de geração e confere o limite de cards do plano.
+// Escreve a resposta de erro e devolve false quando a requisição deve parar.
+func (h *FlashcardHandler) resolveOptions(c *gin.Context, userID uuid.UUID, opts *model.GenerationOptions) bool {
+	resolved, err := h.userService.GenerationOptions(c.Request.Context(), userID, *opts)
+	if err != nil {
+		respondServiceError(c, err, "failed to check generation limits")
+		return false
+	}
+	*opts = resolved
+	return true
+}
 
-	// Validate the incoming JSON payload.
-	if err := c.ShouldBindJSON(&summaryReq); err != nil {
+// resolveSource carrega o arquivo referenciado por source_id, que define o tipo de conteúdo e,
+// se file_name não veio, o nome do set. Escreve a resposta de erro e devolve false quando a
+// requisição deve parar.
+func (h *FlashcardHandler) resolveSource(c *gin.Context, userID uuid.UUID, req *model.SummaryRequest) bool {
+	if req.SourceID == nil {
+		return true
+	}
+
+	source, data, err := h.sourceService.Load(c.Request.Context(), userID, *req.SourceID)
+	if err != nil {
+		respondServiceError(c, err, "failed to load source")
+		return false
+	}
+
+	req.ContentType = source.ContentType
+	req.Content = ""
+	req.Data = data
+	if req.FileName == nil || *req.FileName == "" {
+		req.FileName = &source.FileName
+	}
+	return true
+}
+
+// summaryTopic escolhe o nome do set a partir do arquivo enviado ou do tipo de conteúdo.
+func summaryTopic(req model.SummaryRequest) string {
+	if req.FileName != nil && *req.FileName != "" {
+		return *req.FileName
+	}
+	switch req.ContentType {
+	case "pdf":
+		return "Documento PDF"
+	case "docx", "epub", "html", "markdown":
+		return "Documento de Estudo"
+	case "pptx":
+		return "Slides de Aula"
+	case "srt", "vtt":
+		return "Transcrição de Aula"
+	case "image":
+		return "Imagem de Estudo"
+	}
+	return "Resumo de Estudo"
+}
+
+// GenerateFlashcardsStream handles POST /flashcards/generate/stream. It generates like
+// GenerateFlashcards but synchronously, pushing each card to the client over Server-Sent Events
+// as soon as the model finishes writing it.
+func (h *FlashcardHandler) GenerateFlashcardsStream(c *gin.Context) {
+	var promptReq model.PromptRequest
+	if err := c.ShouldBindJSON(&promptReq); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	// Get user info from context (set by auth middleware)
-	userIDStr, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
 		return
 	}
 
-	userEmail, _ := c.Get("userEmail") // Optional, might be empty
-
-	userID, err := uuid.Parse(userIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+	if !h.resolveOptions(c, userID, &promptReq.GenerationOptions) {
 		return
 	}
 
-	ctx := context.Background()
+	h.streamGeneration(c, userID, promptReq.Prompt, promptReq.Level.OrDefault(), func(ctx context.Context) (model.FlashcardsResponse, error) {
+		return h.generator.GenerateFlashcards(ctx, promptReq)
+	})
+}
 
-	// Ensure user exists in our database
-	email := ""
-	if userEmail != nil {
-		email = userEmail.(string)
-	}
-	
-	_, err = h.userService.EnsureUserExists(ctx, userID, email)
-	if err != nil {
-		log.Printf("Error ensuring user exists: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify user"})
+// GenerateFlashcardsFromSummaryStream handles POST /flashcards/generate-from-summary/stream,
+// the streaming counterpart of GenerateFlashcardsFromSummary. Large texts also report the
+// progress of each chunk.
+func (h *FlashcardHandler) GenerateFlashcardsFromSummaryStream(c *gin.Context) {
+	var summaryReq model.SummaryRequest
+	if err := c.ShouldBindJSON(&summaryReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	// Determine topic name based on content type and file name
-	topicName := "Resumo de Estudo"
-	if summaryReq.FileName != nil && *summaryReq.FileName != "" {
-		topicName = *summaryReq.FileName
-	} else if summaryReq.ContentType == "pdf" {
-		topicName = "Documento PDF"
-	} else if summaryReq.ContentType == "image" {
-		topicName = "Imagem de Estudo"
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
 	}
 
-	// 1. Criar o FlashcardSet
-	set := model.FlashcardSet{
-		UserID: userID,
-		Topic:  topicName,
+	if !h.resolveOptions(c, userID, &summaryReq.GenerationOptions) {
+		return
 	}
-	setID, err := h.flashcardSetService.Create(ctx, set)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create flashcard set"})
-		log.Printf("Erro ao criar o flashcard set: %v", err)
+
+	if !h.resolveSource(c, userID, &summaryReq) {
 		return
 	}
 
-	// 2. Generate flashcards from summary content
-	flashcardSet, err := deepseek.GenerateFlashcardsFromSummary(summaryReq.Content, summaryReq.ContentType, summaryReq.Level)
+	h.streamGeneration(c, userID, summaryTopic(summaryReq), summaryReq.Level.OrDefault(), func(ctx context.Context) (model.FlashcardsResponse, error) {
+		return h.generator.GenerateFlashcardsFromSummary(ctx, summaryReq)
+	})
+}
+
+// streamGeneration roda generate enviando os eventos SSE:
+//   - "progress" {done, total}: etapas (chunks) concluídas;
+//   - "card" {index, flashcard}: card pronto, ainda não salvo;
+//   - "done" {flashcard_set_id, flashcards}: set salvo; esta é a lista definitiva;
+//   - "error" {error}: a geração ou o salvamento falhou e nada foi salvo.
+func (h *FlashcardHandler) streamGeneration(c *gin.Context, userID uuid.UUID, topic string, difficulty model.Difficulty, generate func(ctx context.Context) (model.FlashcardsResponse, error)) {
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Evita que proxies (nginx) segurem os eventos em buffer
+	c.Header("X-Accel-Buffering", "no")
+
+	send := func(event string, data any) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	// Os hooks são chamados na goroutine do handler, então podem escrever direto na resposta
+	sent := 0
+	ctx := llm.WithProgress(c.Request.Context(), func(done, total int) {
+		send("progress", gin.H{"done": done, "total": total})
+	})
+	ctx = llm.WithCards(ctx, func(card model.Flashcard) {
+		send("card", gin.H{"index": sent, "flashcard": card})
+		sent++
+	})
+
+	generated, err := generate(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if ctx.Err() == nil {
+			log.Printf("Erro na geração em stream para usuário %s: %v", userID.String(), err)
+			send("error", gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	// 3. Store the generated flashcards
-	stored, err := h.flashcardService.GenerateAndStoreFlashcards(ctx, flashcardSet.Flashcards, setID)
+	set := model.FlashcardSet{UserID: userID, Topic: topic, Difficulty: &difficulty}
+	set, stored, err := h.flashcardService.CreateSetWithFlashcards(ctx, set, generated.Flashcards)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		log.Printf("Erro ao salvar o flashcard set: %v", err)
+		send("error", gin.H{"error": "failed to store flashcard set"})
 		return
 	}
 
-	// Log the generated flashcards for debugging purposes.
-	log.Printf("Criado set ID: %s com %d flashcards do resumo para usuário %s", setID.String(), len(stored), userID.String())
-
-	// Respond with the generated flashcards.
-	c.JSON(http.StatusOK, gin.H{"flashcard_set_id": setID, "flashcards": stored})
+	log.Printf("Criado set ID: %s com %d flashcards (stream) para usuário %s", set.ID.String(), len(stored), userID.String())
+	send("done", gin.H{"flashcard_set_id": set.ID, "flashcards": stored})
 }
 
 func (h *FlashcardHandler) GetFlashcardsBySetID(c *gin.Context) {
@@ -204,54 +317,247 @@ func (h *FlashcardHandler) GetFlashcardsBySetID(c *gin.Context) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := pageFromQuery(c, setFlashcardSorts, model.SortCardOrder, false)
+	if !ok {
+		return
+	}
+
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	log.Printf("Calling flashcard service to get flashcards for set: %s", setID.String())
-	flashcards, err := h.flashcardService.GetAllBySetID(ctx, setID)
+	flashcards, next, err := h.flashcardService.GetAllBySetID(ctx, userID, setID, page)
 	if err != nil {
-		log.Printf("Erro ao obter os flashcards para set %s: %v", setID.String(), err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flashcards"})
+		respondServiceError(c, err, "failed to fetch flashcards")
 		return
 	}
 	
 	log.Printf("Successfully retrieved %d flashcards for set %s", len(flashcards), setID.String())
-	c.JSON(http.StatusOK, gin.H{"flashcards": flashcards})
+	c.JSON(http.StatusOK, gin.H{"flashcards": flashcards, "next_cursor": nextCursor(next)})
 }
 
 func (h *FlashcardHandler) GetFlashcardsByTopic(c *gin.Context) {
-	userIDStr := c.Param("user_id")
 	topic := c.Query("topic")
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := pageFromQuery(c, topicFlashcardSorts, model.SortCardOrder, false)
+	if !ok {
 		return
 	}
 
-	flashcards, err := h.flashcardService.GetFlashcardsByTopic(context.Background(), userID, topic)
+	flashcards, next, err := h.flashcardService.GetFlashcardsByTopic(c.Request.Context(), userID, topic, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flashcards"})
 		log.Println("Erro ao obter os flashcards:", err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"flashcards": flashcards} )
+	c.JSON(http.StatusOK, gin.H{"flashcards": flashcards, "next_cursor": nextCursor(next)})
 }
 
 func (h *FlashcardHandler) GetAllUserFlashcards(c *gin.Context) {
-	userIDStr := c.Param("user_id")
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := pageFromQuery(c, setSorts, model.SortCreatedAt, true)
+	if !ok {
+		return
+	}
+
+	filter, ok := setFilterFromQuery(c)
+	if !ok {
 		return
 	}
 	
-	flashcardSets, err := h.flashcardService.GetAllUserFlashcards(context.Background(), userID)
+	flashcardSets, next, err := h.flashcardService.GetAllUserFlashcards(c.Request.Context(), userID, filter, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flashcards"})
 		log.Println("Erro ao obter os flashcards:", err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user_flashcard_sets": flashcardSets} )
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{"user_flashcard_sets": flashcardSets, "next_cursor": nextCursor(next)})
+}
+
+// CreateFlashcard adds a card written by the user to the end of a set, without calling the LLM.
+func (h *FlashcardHandler) CreateFlashcard(c *gin.Context) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	var input model.FlashcardInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.flashcardService.Create(c.Request.Context(), userID, setID, input)
+	if err != nil {
+		respondServiceError(c, err, "failed to create flashcard")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"flashcard": card})
+}
+
+// ReplaceFlashcard handles PUT: question and answer must both be sent.
+func (h *FlashcardHandler) ReplaceFlashcard(c *gin.Context) {
+	var input model.FlashcardInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	h.updateFlashcard(c, model.FlashcardPatch{QuestionText: &input.QuestionText, AnswerText: &input.AnswerText})
+}
+
+// PatchFlashcard handles PATCH: only the fields sent are changed.
+func (h *FlashcardHandler) PatchFlashcard(c *gin.Context) {
+	var patch model.FlashcardPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	h.updateFlashcard(c, patch)
+}
+
+func (h *FlashcardHandler) updateFlashcard(c *gin.Context, patch model.FlashcardPatch) {
+	flashcardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.flashcardService.Update(c.Request.Context(), userID, flashcardID, patch)
+	if err != nil {
+		respondServiceError(c, err, "failed to update flashcard")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flashcard": card})
+}
+
+func (h *FlashcardHandler) DeleteFlashcard(c *gin.Context) {
+	flashcardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.flashcardService.Delete(c.Request.Context(), userID, flashcardID); err != nil {
+		respondServiceError(c, err, "failed to delete flashcard")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderFlashcards recebe todos os cards do set na nova ordem e devolve o set reordenado.
+func (h *FlashcardHandler) ReorderFlashcards(c *gin.Context) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	var req model.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	cards, err := h.flashcardService.Reorder(c.Request.Context(), userID, setID, req.FlashcardIDs)
+	if err != nil {
+		respondServiceError(c, err, "failed to reorder flashcards")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flashcards": cards})
+}
+
+// MoveFlashcards move cards do set para outro set do usuário e devolve os cards do set de destino.
+func (h *FlashcardHandler) MoveFlashcards(c *gin.Context) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	var req model.MoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	cards, err := h.flashcardService.Move(c.Request.Context(), userID, setID, req)
+	if err != nil {
+		respondServiceError(c, err, "failed to move flashcards")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"target_set_id": req.TargetSetID, "flashcards": cards})
+}
+
+// CopyFlashcards copia cards do set para um set existente ou novo e devolve as cópias.
+func (h *FlashcardHandler) CopyFlashcards(c *gin.Context) {
+	setID, err := uuid.Parse(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard set ID"})
+		return
+	}
+
+	var req model.CopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: send either target_set_id or new_set_topic"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.flashcardService.Copy(c.Request.Context(), userID, setID, req)
+	if err != nil {
+		respondServiceError(c, err, "failed to copy flashcards")
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	jobService services.JobService
+}
+
+func NewJobHandler(js services.JobService) *JobHandler {
+	return &JobHandler{jobService: js}
+}
+
+// GetJob returns the status and progress of a generation job and, once it succeeded,
+// the ID of the flashcard set it created.
+func (h *JobHandler) GetJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.Get(c.Request.Context(), userID, jobID)
+	if err != nil {
+		respondServiceError(c, err, "failed to fetch job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// CancelJob cancels a queued or running job; finished jobs answer 409.
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.Cancel(c.Request.Context(), userID, jobID)
+	if err != nil {
+		respondServiceError(c, err, "failed to cancel job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReviewHandler struct {
+	reviewService services.ReviewService
+	userService   services.UserService
+}
+
+func NewReviewHandler(rs services.ReviewService, us services.UserService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: rs,
+		userService:   us,
+	}
+}
+
+// ReviewFlashcard handles POST requests with the grade the user gave to a flashcard
+// and returns the updated scheduling state (next due date, interval, ease factor).
+func (h *ReviewHandler) ReviewFlashcard(c *gin.Context) {
+	flashcardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flashcard ID"})
+		return
+	}
+
+	var reviewReq model.ReviewRequest
+	if err := c.ShouldBindJSON(&reviewReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.Review(c.Request.Context(), userID, model.ReviewAnswer{
+		FlashcardID:    flashcardID,
+		Grade:          *reviewReq.Grade,
+		ResponseTimeMs: reviewReq.ResponseTimeMs,
+	})
+	if err != nil {
+		respondServiceError(c, err, "failed to review flashcard")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review})
+}
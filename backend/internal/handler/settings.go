@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/scheduler"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	settingsService services.SettingsService
+	userService     services.UserService
+}
+
+func NewSettingsHandler(ss services.SettingsService, us services.UserService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: ss,
+		userService:     us,
+	}
+}
+
+// GetSettings returns the study settings of the authenticated user.
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.Get(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Erro ao obter configurações do usuário %s: %v", userID.String(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateSettings changes the scheduler (sm2 or fsrs), desired retention and daily limits of the authenticated user.
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	var req model.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.Update(c.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("Erro ao atualizar configurações do usuário %s: %v", userID.String(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// OptimizeFSRS fits the FSRS weights to the review history of the authenticated user.
+func (h *SettingsHandler) OptimizeFSRS(c *gin.Context) {
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.OptimizeFSRS(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrNotEnoughHistory) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrOptimizeCooldown) {
+			c.Header("Retry-After", strconv.Itoa(int(services.OptimizeCooldown.Seconds())))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Erro ao otimizar pesos FSRS do usuário %s: %v", userID.String(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to optimize FSRS weights"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SourceHandler struct {
+	sourceService services.SourceService
+	userService   services.UserService
+}
+
+func NewSourceHandler(ss services.SourceService, us services.UserService) *SourceHandler {
+	return &SourceHandler{sourceService: ss, userService: us}
+}
+
+// multipartOverhead é a folga para os cabeçalhos do multipart além do próprio arquivo.
+const multipartOverhead = 1 << 20
+
+// UploadSource handles POST /sources with a multipart/form-data body whose "file" field is the
+// study material. The file is streamed to the blob store without being buffered in memory, and
+// the returned source ID can be sent as source_id to generate-from-summary.
+func (h *SourceHandler) UploadSource(c *gin.Context) {
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, model.MaxSourceBytes+multipartOverhead)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expected a multipart/form-data body"})
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": `missing "file" field`})
+			return
+		}
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		source, err := h.sourceService.Upload(c.Request.Context(), userID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
+		c.Header("Location", "/api/v1/sources/"+source.ID.String())
+		c.JSON(http.StatusCreated, gin.H{"source": source})
+		return
+	}
+}
+
+// GetSource returns the metadata of an uploaded file.
+func (h *SourceHandler) GetSource(c *gin.Context) {
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+		return
+	}
+
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	source, err := h.sourceService.Get(c.Request.Context(), userID, sourceID)
+	if err != nil {
+		respondServiceError(c, err, "failed to fetch source")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"source": source})
+}
+
+// respondUploadError maps the size and format errors of an upload; the rest go to respondServiceError.
+func respondUploadError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, services.ErrSourceTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file is too large (max %d MB)", model.MaxSourceBytes>>20)})
+	case errors.Is(err, services.ErrUnsupportedSource):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error() + "; send a PDF, DOCX, PPTX, EPUB, HTML, Markdown or plain text file, SRT or VTT subtitles, or an image (JPEG, PNG, WebP, GIF)"})
+	default:
+		respondServiceError(c, err, "failed to store file")
+	}
+}
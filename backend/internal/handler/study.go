@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StudyHandler struct {
+	studyService services.StudyService
+	userService  services.UserService
+}
+
+func NewStudyHandler(ss services.StudyService, us services.UserService) *StudyHandler {
+	return &StudyHandler{
+		studyService: ss,
+		userService:  us,
+	}
+}
+
+// GetDueQueue returns the cards due today across every set of the authenticated user.
+// The optional "tz" query parameter (IANA name, e.g. America/Sao_Paulo) defines when "today" ends; UTC by default.
+func (h *StudyHandler) GetDueQueue(c *gin.Context) {
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+			return
+		}
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	queue, err := h.studyService.GetDueQueue(c.Request.Context(), userID, time.Now().In(loc))
+	if err != nil {
+		log.Printf("Erro ao montar a fila de estudo do usuário %s: %v", userID.String(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch due cards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// StartSession opens a study session for one or many sets of the authenticated user.
+func (h *StudyHandler) StartSession(c *gin.Context) {
+	var req model.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	session, err := h.studyService.StartSession(c.Request.Context(), userID, req.SetIDs)
+	if err != nil {
+		respondServiceError(c, err, "failed to start study session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"session": session})
+}
+
+func (h *StudyHandler) GetSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	session, err := h.studyService.GetSession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		respondServiceError(c, err, "failed to fetch study session")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+// RecordAnswer stores one answer (grade, response time) given during the session and reschedules the card.
+func (h *StudyHandler) RecordAnswer(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var req model.SessionAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	review, err := h.studyService.RecordAnswer(c.Request.Context(), userID, sessionID, req)
+	if err != nil {
+		respondServiceError(c, err, "failed to record answer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review})
+}
+
+// FinishSession closes the session and returns a summary of the answers.
+func (h *StudyHandler) FinishSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	userID, ok := userFromContext(c, h.userService)
+	if !ok {
+		return
+	}
+
+	session, summary, err := h.studyService.FinishSession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		respondServiceError(c, err, "failed to finish study session")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session, "summary": summary})
+}
@@ -0,0 +1,82 @@
+package ingestion
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SniffLen é quantos bytes do começo do arquivo DetectContentType precisa ver.
+const SniffLen = 512
+
+// zipTypes mapeia a extensão dos formatos que são zips para o content_type e o MIME type.
+// O começo de um zip não diz o que há dentro, então para eles vale a extensão; o extrator
+// confere a estrutura depois.
+var zipTypes = map[string][2]string{
+	".docx": {"docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	".pptx": {"pptx", "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+	".epub": {"epub", "application/epub+zip"},
+}
+
+// DetectContentType identifica o content_type de geração ("pdf", "image", "docx", "pptx",
+// "epub", "html", "markdown", "srt", "vtt" ou "text") e o MIME type a partir dos primeiros
+// bytes do arquivo.
+// O nome só desempata o que os bytes não distinguem: o formato dentro de um zip e Markdown ou
+// SRT dentro de texto puro. ok é false para formatos que a geração não sabe ler.
+func DetectContentType(fileName string, head []byte) (contentType, mimeType string, ok bool) {
+	if mimeType := sniffImage(head); mimeType != "" {
+		return "image", mimeType, true
+	}
+
+	ext := strings.ToLower(path.Ext(fileName))
+	detected := http.DetectContentType(head)
+	switch {
+	case detected == "application/pdf":
+		return "pdf", detected, true
+	case detected == "application/zip":
+		// O EPUB começa com a entrada "mimetype" sem compressão, então dá para reconhecê-lo pelos bytes
+		if bytes.Contains(head[:min(len(head), 64)], []byte("mimetypeapplication/epub+zip")) {
+			ext = ".epub"
+		}
+		if t, ok := zipTypes[ext]; ok {
+			return t[0], t[1], true
+		}
+	case strings.HasPrefix(detected, "text/html"), strings.HasPrefix(detected, "text/xml") && (ext == ".xhtml" || ext == ".html" || ext == ".htm"):
+		return "html", "text/html", true
+	case strings.HasPrefix(detected, "text/plain"):
+		// DetectContentType só olha o começo; confere que não é binário disfarçado
+		if !utf8.Valid(trimPartialRune(head)) {
+			break
+		}
+		if bytes.HasPrefix(bytes.TrimPrefix(head, []byte("\ufeff")), []byte("WEBVTT")) {
+			return "vtt", "text/vtt", true
+		}
+		if ext == ".srt" || srtStart.Match(head) {
+			return "srt", "application/x-subrip", true
+		}
+		if ext == ".md" || ext == ".markdown" {
+			return "markdown", "text/markdown", true
+		}
+		if ext == ".html" || ext == ".htm" {
+			return "html", "text/html", true
+		}
+		return "text", "text/plain", true
+	}
+	return "", detected, false
+}
+
+// srtStart reconhece o primeiro bloco de um SRT: o número da legenda e a linha de tempo.
+var srtStart = regexp.MustCompile(`^\x{FEFF}?\s*\d+\r?\n\d{2}:\d{2}:\d{2},\d{3} -->`)
+
+// trimPartialRune descarta uma sequência UTF-8 cortada no fim do trecho lido.
+func trimPartialRune(b []byte) []byte {
+	for i := 0; i < utf8.UTFMax && i < len(b); i++ {
+		if utf8.Valid(b[:len(b)-i]) {
+			return b[:len(b)-i]
+		}
+	}
+	return b
+}
@@ -0,0 +1,109 @@
+// Package ingestion prepara o material de estudo enviado pelo usuário para a geração: documentos
+// (PDF, ...) viram texto dividido em seções rotuladas, pronto para o chunking e para os prompts,
+// e imagens são validadas antes de seguir para o modelo de visão.
+package ingestion
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Erros devolvidos quando o documento não pode ser usado. Todos são culpa do arquivo enviado,
+// não do servidor, e podem ser mostrados ao usuário.
+var (
+	// ErrInvalidDocument indica um arquivo corrompido ou que não é do formato informado.
+	ErrInvalidDocument = errors.New("the file is not a valid document")
+	// ErrEncrypted indica um PDF protegido por senha ou criptografado.
+	ErrEncrypted = errors.New("the PDF is encrypted or password protected; remove the protection and upload it again")
+	// ErrScanned indica um documento sem camada de texto (páginas escaneadas ou só imagens).
+	ErrScanned = errors.New("the PDF has no extractable text (it looks scanned); upload a PDF with selectable text")
+	// ErrEmptyDocument indica um documento (DOCX, PPTX, ...) sem texto aproveitável.
+	ErrEmptyDocument = errors.New("the document has no extractable text")
+	// ErrUnsupportedImage indica uma imagem em formato não aceito ou diferente do declarado.
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	// ErrImageTooLarge indica uma imagem acima de MaxImageBytes.
+	ErrImageTooLarge = errors.New("the image is larger than 10MB")
+)
+
+// IsRejected diz se err é um dos erros de documento acima, que devem virar resposta 4xx.
+func IsRejected(err error) bool {
+	for _, target := range []error{ErrInvalidDocument, ErrEncrypted, ErrScanned, ErrEmptyDocument, ErrUnsupportedImage, ErrImageTooLarge} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Section é um trecho do documento com um rótulo que o localiza, como "Página 3".
+type Section struct {
+	Label string
+	Text  string
+}
+
+// Document é o texto extraído, na ordem original.
+type Document struct {
+	Sections []Section
+}
+
+// Text junta as seções com o rótulo de cada uma entre colchetes, separadas por linha em branco,
+// que é onde utils.ChunkContent prefere cortar.
+func (d Document) Text() string {
+	var b strings.Builder
+	for i, s := range d.Sections {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		if s.Label != "" {
+			fmt.Fprintf(&b, "[%s]\n", s.Label)
+		}
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// requireText devolve o documento ou ErrEmptyDocument se ele não tem texto suficiente.
+func (d Document) requireText() (Document, error) {
+	if countTextRunes(d.Text()) < minTextRunes {
+		return Document{}, ErrEmptyDocument
+	}
+	return d, nil
+}
+
+// countTextRunes conta letras e números, ignorando pontuação, espaços e os rótulos vazios.
+func countTextRunes(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// DecodeBase64 decodifica o conteúdo enviado em JSON, aceitando também o formato data URI
+// ("data:application/pdf;base64,...").
+func DecodeBase64(content string) ([]byte, error) {
+	if strings.HasPrefix(content, "data:") {
+		if _, payload, ok := strings.Cut(content, ","); ok {
+			content = payload
+		}
+	}
+	content = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, content)
+
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(content); err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 content", ErrInvalidDocument)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,60 @@
+package ingestion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtractDOCX lê o texto de um documento do Word. Cada título (estilos Heading/Título) abre uma
+// seção rotulada com o texto dele; o que vem antes do primeiro título fica numa seção sem rótulo.
+func ExtractDOCX(data []byte) (Document, error) {
+	z, err := openZip(data)
+	if err != nil {
+		return Document{}, err
+	}
+	body, ok, err := z.read("word/document.xml")
+	if err != nil {
+		return Document{}, err
+	}
+	if !ok {
+		return Document{}, fmt.Errorf("%w: word/document.xml not found", ErrInvalidDocument)
+	}
+
+	var doc Document
+	var current Section
+	var paragraphs []string
+	flush := func() {
+		if len(paragraphs) > 0 {
+			current.Text = strings.Join(paragraphs, "\n\n")
+			doc.Sections = append(doc.Sections, current)
+		}
+		paragraphs = nil
+	}
+
+	err = ooxmlParagraphs(body, func(text, style string) {
+		if isHeadingStyle(style) {
+			flush()
+			current = Section{Label: text}
+			return
+		}
+		paragraphs = append(paragraphs, text)
+	})
+	if err != nil {
+		return Document{}, err
+	}
+	flush()
+
+	return doc.requireText()
+}
+
+// isHeadingStyle reconhece os estilos de título do Word em inglês e português
+// ("Heading1", "Title", "Ttulo1", ...), inclusive os IDs sem acento que o Word grava.
+func isHeadingStyle(style string) bool {
+	s := strings.ToLower(style)
+	for _, prefix := range []string{"heading", "title", "titulo", "ttulo", "título"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
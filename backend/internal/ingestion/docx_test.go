@@ -0,0 +1,81 @@
+package ingestion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const docxNamespace = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+func docxFile(t *testing.T, body string) []byte {
+	t.Helper()
+	return buildZip(t,
+		zipEntry{"[Content_Types].xml", `<Types/>`},
+		zipEntry{"word/document.xml", `<w:document ` + docxNamespace + `><w:body>` + body + `</w:body></w:document>`},
+	)
+}
+
+func docxParagraph(style, text string) string {
+	p := `<w:p>`
+	if style != "" {
+		p += `<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`
+	}
+	return p + `<w:r><w:t>` + text + `</w:t></w:r></w:p>`
+}
+
+func TestExtractDOCX(t *testing.T) {
+	body := docxParagraph("", "Apostila de cardiologia para o segundo ano.") +
+		docxParagraph("Ttulo1", "Sistema cardiovascular") +
+		`<w:p><w:r><w:t xml:space="preserve">O coração</w:t><w:tab/><w:t>bombeia sangue.</w:t></w:r></w:p>` +
+		`<w:p></w:p>` +
+		docxParagraph("Heading2", "Valvas") +
+		`<w:p><w:r><w:t>São quatro valvas.</w:t><w:br/><w:t>Mitral e tricúspide.</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc>` + docxParagraph("", "Aórtica") + `</w:tc></w:tr></w:tbl>`
+
+	doc, err := ExtractDOCX(docxFile(t, body))
+	if err != nil {
+		t.Fatalf("ExtractDOCX: %v", err)
+	}
+	want := []Section{
+		{Label: "", Text: "Apostila de cardiologia para o segundo ano."},
+		{Label: "Sistema cardiovascular", Text: "O coração bombeia sangue."},
+		{Label: "Valvas", Text: "São quatro valvas.\nMitral e tricúspide.\n\nAórtica"},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractDOCXErrors(t *testing.T) {
+	noBody := buildZip(t, zipEntry{"word/styles.xml", "<w:styles/>"})
+	if _, err := ExtractDOCX(noBody); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("missing document.xml: err = %v, want ErrInvalidDocument", err)
+	}
+	if _, err := ExtractDOCX(docxFile(t, docxParagraph("Title", "Só um título"))); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("only headings: err = %v, want ErrEmptyDocument", err)
+	}
+	if _, err := ExtractDOCX(docxFile(t, "<w:p><w:r><w:t>aberto")); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("broken XML: err = %v, want ErrInvalidDocument", err)
+	}
+}
+
+func TestIsHeadingStyle(t *testing.T) {
+	tests := map[string]bool{
+		"Heading1":      true,
+		"heading 2":     true,
+		"Title":         true,
+		"Ttulo1":        true,
+		"Título2":       true,
+		"titulo3":       true,
+		"":              false,
+		"Normal":        false,
+		"Subtitle":      false,
+		"ListParagraph": false,
+	}
+	for style, want := range tests {
+		if got := isHeadingStyle(style); got != want {
+			t.Errorf("isHeadingStyle(%q) = %v, want %v", style, got, want)
+		}
+	}
+}
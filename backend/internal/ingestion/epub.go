@@ -0,0 +1,136 @@
+package ingestion
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ExtractEPUB lê os capítulos de um livro EPUB na ordem de leitura (spine). Cada arquivo de
+// capítulo passa pela mesma extração do HTML e é dividido pelos títulos internos; o que vem
+// antes do primeiro título recebe o nome do capítulo no sumário ou "Capítulo N".
+func ExtractEPUB(data []byte) (Document, error) {
+	z, err := openZip(data)
+	if err != nil {
+		return Document{}, err
+	}
+
+	opfPath, err := epubPackagePath(z)
+	if err != nil {
+		return Document{}, err
+	}
+	raw, ok, err := z.read(opfPath)
+	if err != nil {
+		return Document{}, err
+	}
+	if !ok {
+		return Document{}, fmt.Errorf("%w: %s not found", ErrInvalidDocument, opfPath)
+	}
+
+	var pkg struct {
+		Manifest []struct {
+			ID        string `xml:"id,attr"`
+			Href      string `xml:"href,attr"`
+			MediaType string `xml:"media-type,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err := xml.Unmarshal(raw, &pkg); err != nil {
+		return Document{}, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, opfPath, err)
+	}
+
+	hrefs := map[string]string{}
+	for _, item := range pkg.Manifest {
+		if strings.Contains(item.MediaType, "html") {
+			hrefs[item.ID] = resolvePath(path.Dir(opfPath)+"/", item.Href)
+		}
+	}
+
+	var doc Document
+	chapter := 0
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok || ref.Linear == "no" {
+			continue
+		}
+		body, ok, err := z.read(unescapeHref(href))
+		if err != nil {
+			return Document{}, err
+		}
+		if !ok {
+			continue
+		}
+		root, err := parseHTML(body)
+		if err != nil {
+			return Document{}, err
+		}
+
+		sections := htmlSections(bodyOf(root), "")
+		if len(sections) == 0 {
+			continue
+		}
+		chapter++
+		if sections[0].Label == "" {
+			sections[0].Label = pageTitle(root)
+			if sections[0].Label == "" {
+				sections[0].Label = fmt.Sprintf("Capítulo %d", chapter)
+			}
+		}
+		doc.Sections = append(doc.Sections, sections...)
+	}
+
+	return doc.requireText()
+}
+
+// epubPackagePath lê META-INF/container.xml, que aponta o arquivo .opf com o manifesto e o spine.
+func epubPackagePath(z *zipDocument) (string, error) {
+	data, ok, err := z.read("META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: META-INF/container.xml not found", ErrInvalidDocument)
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := xml.Unmarshal(data, &container); err != nil || len(container.Rootfiles) == 0 {
+		return "", fmt.Errorf("%w: invalid META-INF/container.xml", ErrInvalidDocument)
+	}
+	return strings.TrimPrefix(container.Rootfiles[0].FullPath, "/"), nil
+}
+
+// bodyOf devolve o <body> do documento (ou a raiz, se não houver). Capítulos de EPUB são
+// páginas inteiras de conteúdo, então não passam pela escolha do bloco principal.
+func bodyOf(root *html.Node) *html.Node {
+	var body *html.Node
+	walkHTML(root, func(n *html.Node) bool {
+		if body == nil && n.Type == html.ElementNode && n.DataAtom == atom.Body {
+			body = n
+		}
+		return body == nil
+	})
+	if body == nil {
+		return root
+	}
+	return body
+}
+
+// unescapeHref desfaz o percent-encoding dos hrefs do manifesto ("cap%201.xhtml").
+func unescapeHref(href string) string {
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		return unescaped
+	}
+	return href
+}
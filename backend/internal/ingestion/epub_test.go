@@ -0,0 +1,52 @@
+package ingestion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func xhtml(title, body string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><html xmlns="http://www.w3.org/1999/xhtml"><head><title>` +
+		title + `</title></head><body>` + body + `</body></html>`
+}
+
+func TestExtractEPUB(t *testing.T) {
+	// O spine lê cap2 antes de cap 1; o sumário (linear="no") e a folha de estilo ficam de fora
+	data := buildZip(t,
+		zipEntry{"mimetype", "application/epub+zip"},
+		zipEntry{"META-INF/container.xml", `<container xmlns="urn:oasis:names:tc:opendocument:xmlns:container" version="1.0">` +
+			`<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`},
+		zipEntry{"OEBPS/content.opf", `<package xmlns="http://www.idpf.org/2007/opf" version="3.0"><manifest>` +
+			`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` +
+			`<item id="css" href="estilo.css" media-type="text/css"/>` +
+			`<item id="c1" href="Texto/cap%201.xhtml" media-type="application/xhtml+xml"/>` +
+			`<item id="c2" href="Texto/cap2.xhtml" media-type="application/xhtml+xml"/>` +
+			`</manifest><spine><itemref idref="nav" linear="no"/><itemref idref="c2"/><itemref idref="c1"/></spine></package>`},
+		zipEntry{"OEBPS/nav.xhtml", xhtml("Sumário", `<nav><ol><li>Capítulo um</li></ol></nav><p>Índice do livro inteiro.</p>`)},
+		zipEntry{"OEBPS/estilo.css", "p { margin: 0 }"},
+		zipEntry{"OEBPS/Texto/cap2.xhtml", xhtml("Prefácio", `<p>Este livro reúne as aulas de fisiologia.</p>`)},
+		zipEntry{"OEBPS/Texto/cap 1.xhtml", xhtml("", `<p>Abertura do capítulo sobre o coração.</p>`+
+			`<h2>Ciclo cardíaco</h2><p>Sístole e diástole se alternam.</p>`)},
+	)
+
+	doc, err := ExtractEPUB(data)
+	if err != nil {
+		t.Fatalf("ExtractEPUB: %v", err)
+	}
+	want := []Section{
+		{Label: "Prefácio", Text: "Este livro reúne as aulas de fisiologia."},
+		{Label: "Capítulo 2", Text: "Abertura do capítulo sobre o coração."},
+		{Label: "Ciclo cardíaco", Text: "Sístole e diástole se alternam."},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractEPUBWithoutContainer(t *testing.T) {
+	data := buildZip(t, zipEntry{"mimetype", "application/epub+zip"}, zipEntry{"OEBPS/content.opf", "<package/>"})
+	if _, err := ExtractEPUB(data); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("err = %v, want ErrInvalidDocument", err)
+	}
+}
@@ -0,0 +1,40 @@
+package ingestion
+
+import "fmt"
+
+// extractors são os formatos de documento que viram texto, pelo content_type de geração.
+var extractors = map[string]func(data []byte) (Document, error){
+	"pdf":      ExtractPDF,
+	"docx":     ExtractDOCX,
+	"pptx":     ExtractPPTX,
+	"epub":     ExtractEPUB,
+	"html":     ExtractHTML,
+	"markdown": ExtractMarkdown,
+	"srt":      ExtractSubtitles,
+	"vtt":      ExtractSubtitles,
+}
+
+// IsDocument diz se o content_type é um documento que passa por Extract.
+func IsDocument(contentType string) bool {
+	_, ok := extractors[contentType]
+	return ok
+}
+
+// IsBinary diz se o conteúdo desse tipo vem em base64 quando enviado em JSON. HTML, Markdown
+// e legendas, como "text", vêm como texto puro.
+func IsBinary(contentType string) bool {
+	switch contentType {
+	case "pdf", "docx", "pptx", "epub", "image":
+		return true
+	}
+	return false
+}
+
+// Extract lê o texto de um documento, dividido em seções rotuladas.
+func Extract(contentType string, data []byte) (Document, error) {
+	extract, ok := extractors[contentType]
+	if !ok {
+		return Document{}, fmt.Errorf("%w: unsupported document type %q", ErrInvalidDocument, contentType)
+	}
+	return extract(data)
+}
@@ -0,0 +1,287 @@
+package ingestion
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// ExtractHTML lê o conteúdo principal de uma página, no estilo do modo leitura dos navegadores:
+// descarta menus, cabeçalhos, rodapés e barras laterais, escolhe o bloco com mais texto corrido
+// e divide esse bloco em seções pelos títulos (h1-h6).
+func ExtractHTML(data []byte) (Document, error) {
+	root, err := parseHTML(data)
+	if err != nil {
+		return Document{}, err
+	}
+	doc := Document{Sections: htmlSections(mainContent(root), pageTitle(root))}
+	return doc.requireText()
+}
+
+// parseHTML decodifica o charset declarado (meta charset ou BOM; UTF-8 se não houver) e monta a árvore.
+func parseHTML(data []byte) (*html.Node, error) {
+	r, err := charset.NewReader(bytes.NewReader(data), "text/html")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return root, nil
+}
+
+// Elementos que nunca fazem parte do conteúdo principal.
+var htmlSkipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Svg: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Form: true,
+	atom.Iframe: true, atom.Button: true, atom.Select: true, atom.Head: true,
+}
+
+// Classes e IDs que indicam navegação ou publicidade, e os que indicam conteúdo.
+var (
+	htmlNegative = regexp.MustCompile(`(?i)\b(nav|menu|sidebar|footer|header|comment|share|social|cookie|banner|advert|ads?|promo|related|breadcrumb|popup|modal|subscribe)\b`)
+	htmlPositive = regexp.MustCompile(`(?i)\b(article|content|main|post|entry|text|body|chapter)\b`)
+)
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// skipped diz se o elemento (e tudo dentro dele) deve ser ignorado.
+func skipped(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if htmlSkipped[n.DataAtom] || attr(n, "hidden") != "" || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	switch attr(n, "role") {
+	case "navigation", "banner", "contentinfo", "complementary":
+		return true
+	}
+	hint := attr(n, "class") + " " + attr(n, "id")
+	return htmlNegative.MatchString(hint) && !htmlPositive.MatchString(hint)
+}
+
+func pageTitle(root *html.Node) string {
+	var title string
+	walkHTML(root, func(n *html.Node) bool {
+		if title == "" && n.Type == html.ElementNode && n.DataAtom == atom.Title {
+			title = collapseSpaces(textContent(n))
+		}
+		return title == ""
+	})
+	return title
+}
+
+// mainContent escolhe o nó com o conteúdo principal: o maior <article>/<main> se houver;
+// senão, o contêiner cujos parágrafos somam mais pontos (texto longo, vírgulas, poucos links).
+func mainContent(root *html.Node) *html.Node {
+	var best *html.Node
+	bestLen := 0
+	walkHTML(root, func(n *html.Node) bool {
+		if skipped(n) {
+			return false
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Article || n.DataAtom == atom.Main || attr(n, "role") == "main") {
+			if l := len(textContent(n)); l > bestLen {
+				best, bestLen = n, l
+			}
+		}
+		return true
+	})
+	if best != nil {
+		return best
+	}
+
+	scores := map[*html.Node]float64{}
+	walkHTML(root, func(n *html.Node) bool {
+		if skipped(n) {
+			return false
+		}
+		if n.Type != html.ElementNode || (n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Li && n.DataAtom != atom.Td) {
+			return true
+		}
+		text := collapseSpaces(textContent(n))
+		if len(text) < 25 {
+			return false
+		}
+		score := (1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)) * (1 - linkDensity(n))
+		if parent := n.Parent; parent != nil {
+			scores[parent] += score
+			if grand := parent.Parent; grand != nil {
+				scores[grand] += score / 2
+			}
+		}
+		return false
+	})
+
+	best = root
+	bestScore := 0.0
+	for n, score := range scores {
+		if hint := attr(n, "class") + " " + attr(n, "id"); htmlPositive.MatchString(hint) {
+			score *= 1.25
+		}
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	return best
+}
+
+// linkDensity é a fração do texto do nó que está dentro de links.
+func linkDensity(n *html.Node) float64 {
+	total := len(textContent(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walkHTML(n, func(c *html.Node) bool {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += len(textContent(c))
+			return false
+		}
+		return true
+	})
+	return float64(linked) / float64(total)
+}
+
+// Elementos de bloco que viram parágrafos próprios.
+var htmlBlocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Li: true, atom.Pre: true, atom.Blockquote: true,
+	atom.Td: true, atom.Th: true, atom.Dd: true, atom.Dt: true, atom.Figcaption: true,
+	atom.Tr: true, atom.Table: true, atom.Ul: true, atom.Ol: true, atom.Section: true,
+	atom.Article: true, atom.Main: true, atom.Body: true, atom.Br: true, atom.Hr: true,
+}
+
+var htmlHeadings = map[atom.Atom]bool{
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// htmlSections percorre o conteúdo principal abrindo uma seção a cada título. O texto antes do
+// primeiro título fica sob fallbackLabel.
+func htmlSections(content *html.Node, fallbackLabel string) []Section {
+	var sections []Section
+	current := Section{Label: fallbackLabel}
+	var paragraphs []string
+	var line strings.Builder
+	// Itens de lista e linhas de tabela seguidos ficam no mesmo parágrafo, um por linha
+	item, lastWasItem := false, false
+
+	endLine := func() {
+		if text := collapseSpaces(line.String()); text != "" {
+			if item && lastWasItem {
+				paragraphs[len(paragraphs)-1] += "\n" + text
+			} else {
+				paragraphs = append(paragraphs, text)
+			}
+			lastWasItem = item
+		}
+		line.Reset()
+	}
+	flush := func() {
+		endLine()
+		if len(paragraphs) > 0 {
+			current.Text = strings.Join(paragraphs, "\n\n")
+			sections = append(sections, current)
+		}
+		paragraphs = nil
+	}
+
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if skipped(n) {
+			return
+		}
+		switch {
+		case n.Type == html.TextNode:
+			line.WriteString(n.Data)
+			return
+		case n.Type == html.ElementNode && htmlHeadings[n.DataAtom]:
+			flush()
+			current = Section{Label: collapseSpaces(textContent(n))}
+			return
+		case n.Type == html.ElementNode && n.DataAtom == atom.Img:
+			// O texto alternativo de figuras costuma descrever o que importa (esquemas, exames)
+			if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+				line.WriteString(" [Imagem: " + alt + "] ")
+			}
+			return
+		}
+
+		block := n.Type == html.ElementNode && htmlBlocks[n.DataAtom]
+		isItem := block && (n.DataAtom == atom.Li || n.DataAtom == atom.Tr)
+		if block {
+			endLine()
+			item = isItem
+			if n.DataAtom == atom.Li {
+				line.WriteString("- ")
+			}
+		}
+		cells := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			// Células da mesma linha da tabela ficam juntas, separadas por "|"
+			if n.DataAtom == atom.Tr && c.Type == html.ElementNode {
+				if cells > 0 {
+					line.WriteString(" | ")
+				}
+				cells++
+				for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+					visit(cc)
+				}
+				continue
+			}
+			visit(c)
+		}
+		if block {
+			item = isItem
+			endLine()
+			item = false
+		}
+	}
+
+	visit(content)
+	flush()
+	return sections
+}
+
+// walkHTML visita n e os descendentes em pré-ordem; fn devolve false para não descer num nó.
+func walkHTML(n *html.Node, fn func(*html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, fn)
+	}
+}
+
+// textContent é o texto visível de n, sem os elementos ignorados.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	walkHTML(n, func(c *html.Node) bool {
+		if skipped(c) {
+			return false
+		}
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+			b.WriteByte(' ')
+		}
+		return true
+	})
+	return b.String()
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
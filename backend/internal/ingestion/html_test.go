@@ -0,0 +1,75 @@
+package ingestion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExtractHTMLPicksMainContent(t *testing.T) {
+	// Sem <article> nem <main>: o bloco com parágrafos longos ganha do menu, da lista de
+	// links e do rodapé
+	page := `<!DOCTYPE html><html><head><title>Hipertensão</title><script>var menu = "não";</script></head><body>
+<nav><a href="/">Início</a> <a href="/sobre">Sobre nós e nossa equipe de médicos</a></nav>
+<div class="menu-lateral"><p>Cardiologia, pneumologia, nefrologia, endocrinologia e outras especialidades.</p></div>
+<div id="post">
+  <p>A hipertensão arterial é uma condição clínica, multifatorial, caracterizada por níveis elevados de pressão.</p>
+  <h2>Tratamento</h2>
+  <p>O tratamento inclui mudanças no estilo de vida, dieta com pouco sal, e medicamentos quando necessário.</p>
+  <ul><li>Diuréticos</li><li>IECA</li></ul>
+  <p>Veja o esquema: <img src="sraa.png" alt="Esquema do SRAA"></p>
+  <table><tr><th>Classe</th><th>Exemplo</th></tr><tr><td>BRA</td><td>Losartana</td></tr></table>
+  <div class="share">Compartilhe no Facebook, no Twitter, no WhatsApp e por e-mail com os amigos.</div>
+</div>
+<div class="relacionados"><p><a href="/outro">Leia também: outro artigo muito interessante sobre saúde</a></p></div>
+<footer><p>Copyright 2024, todos os direitos reservados, portal de saúde e bem-estar.</p></footer>
+</body></html>`
+
+	doc, err := ExtractHTML([]byte(page))
+	if err != nil {
+		t.Fatalf("ExtractHTML: %v", err)
+	}
+	want := []Section{
+		{Label: "Hipertensão", Text: "A hipertensão arterial é uma condição clínica, multifatorial, caracterizada por níveis elevados de pressão."},
+		{Label: "Tratamento", Text: "O tratamento inclui mudanças no estilo de vida, dieta com pouco sal, e medicamentos quando necessário.\n\n" +
+			"- Diuréticos\n- IECA\n\nVeja o esquema: [Imagem: Esquema do SRAA]\n\nClasse | Exemplo\nBRA | Losartana"},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractHTMLPrefersArticle(t *testing.T) {
+	page := `<html><body>
+<div><p>Texto longo fora do artigo, com vírgulas, muitas vírgulas, e ainda mais vírgulas, para pontuar alto.</p></div>
+<article><h1>Asma</h1><p>Doença inflamatória crônica das vias aéreas.</p><aside>Anúncio de inalador.</aside></article>
+</body></html>`
+
+	doc, err := ExtractHTML([]byte(page))
+	if err != nil {
+		t.Fatalf("ExtractHTML: %v", err)
+	}
+	want := []Section{{Label: "Asma", Text: "Doença inflamatória crônica das vias aéreas."}}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractHTMLCharset(t *testing.T) {
+	// "Coração" em ISO-8859-1, declarado no meta
+	page := []byte("<html><head><meta charset=\"iso-8859-1\"></head><body><p>Cora\xe7\xe3o: m\xfasculo estriado card\xedaco.</p></body></html>")
+	doc, err := ExtractHTML(page)
+	if err != nil {
+		t.Fatalf("ExtractHTML: %v", err)
+	}
+	if got := doc.Text(); got != "Coração: músculo estriado cardíaco." {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestExtractHTMLWithoutText(t *testing.T) {
+	page := `<html><body><nav><a href="/">Início</a></nav><script>console.log("só script")</script></body></html>`
+	if _, err := ExtractHTML([]byte(page)); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("err = %v, want ErrEmptyDocument", err)
+	}
+}
@@ -0,0 +1,76 @@
+package ingestion
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MaxImageBytes é o maior arquivo de imagem aceito (depois de decodificado).
+const MaxImageBytes = 10 << 20
+
+// ImageTypes são os formatos aceitos pelos modelos de visão, identificados pelo conteúdo.
+var ImageTypes = []string{"image/jpeg", "image/png", "image/webp", "image/gif"}
+
+// Image é uma imagem já validada.
+type Image struct {
+	MIMEType string
+	Data     []byte
+}
+
+// DataURI devolve a imagem no formato aceito em image_url ("data:image/png;base64,...").
+func (img Image) DataURI() string {
+	return "data:" + img.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(img.Data)
+}
+
+// DecodeImage decodifica a imagem enviada em base64 (crua ou como data URI) e confere tamanho
+// e formato. O tipo vem dos bytes, não do que o cliente declarou; se o data URI declarar um
+// tipo diferente do real, a imagem é recusada.
+func DecodeImage(content string) (Image, error) {
+	// Checa o tamanho antes de decodificar: base64 ocupa 4/3 do arquivo
+	if len(content)/4*3 > MaxImageBytes+MaxImageBytes/100 {
+		return Image{}, ErrImageTooLarge
+	}
+
+	declared := ""
+	if rest, ok := strings.CutPrefix(content, "data:"); ok {
+		if meta, _, ok := strings.Cut(rest, ","); ok {
+			declared, _, _ = strings.Cut(meta, ";")
+		}
+	}
+
+	data, err := DecodeBase64(content)
+	if err != nil {
+		return Image{}, err
+	}
+	return ValidateImage(data, declared)
+}
+
+// ValidateImage confere tamanho e formato de uma imagem já em bytes (como as enviadas em
+// POST /sources). declared é o tipo informado pelo cliente, se houver.
+func ValidateImage(data []byte, declared string) (Image, error) {
+	if len(data) > MaxImageBytes {
+		return Image{}, ErrImageTooLarge
+	}
+
+	mimeType := sniffImage(data)
+	if mimeType == "" {
+		return Image{}, fmt.Errorf("%w: expected one of %s", ErrUnsupportedImage, strings.Join(ImageTypes, ", "))
+	}
+	if declared != "" && !strings.EqualFold(declared, mimeType) && !(declared == "image/jpg" && mimeType == "image/jpeg") {
+		return Image{}, fmt.Errorf("%w: declared %s but the file is %s", ErrUnsupportedImage, declared, mimeType)
+	}
+	return Image{MIMEType: mimeType, Data: data}, nil
+}
+
+// sniffImage identifica o formato pelos primeiros bytes; vazio se não for um dos ImageTypes.
+func sniffImage(data []byte) string {
+	detected := http.DetectContentType(data)
+	for _, t := range ImageTypes {
+		if detected == t {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package ingestion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ExtractMarkdown divide um texto Markdown em seções pelos títulos (# e sublinhados com === ou
+// ---) e tira a marcação que só atrapalharia o prompt: ênfase, links, imagens, HTML e o
+// front matter. Blocos de código são mantidos como texto.
+func ExtractMarkdown(data []byte) (Document, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines = skipFrontMatter(lines)
+
+	var doc Document
+	var current Section
+	var paragraphs []string
+	var paragraph []string
+
+	endParagraph := func() {
+		if text := strings.TrimSpace(strings.Join(paragraph, "\n")); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+		paragraph = nil
+	}
+	startSection := func(label string) {
+		endParagraph()
+		if len(paragraphs) > 0 {
+			current.Text = strings.Join(paragraphs, "\n\n")
+			doc.Sections = append(doc.Sections, current)
+		}
+		paragraphs = nil
+		current = Section{Label: label}
+	}
+
+	inFence := false
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if inFence {
+			if strings.HasPrefix(trimmed, fence) {
+				inFence = false
+				endParagraph()
+				continue
+			}
+			paragraph = append(paragraph, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			endParagraph()
+			inFence, fence = true, trimmed[:3]
+			continue
+		}
+
+		if m := mdATXHeading.FindStringSubmatch(trimmed); m != nil {
+			startSection(cleanInlineMarkdown(m[1]))
+			continue
+		}
+		// Título sublinhado: a linha seguinte é só === ou ---
+		if trimmed != "" && len(paragraph) == 0 && i+1 < len(lines) && mdSetextUnderline.MatchString(strings.TrimSpace(lines[i+1])) {
+			startSection(cleanInlineMarkdown(trimmed))
+			i++
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			endParagraph()
+		case mdRule.MatchString(trimmed), mdTableSeparator.MatchString(trimmed), mdLinkDefinition.MatchString(trimmed):
+			// Linhas só de marcação
+		default:
+			paragraph = append(paragraph, cleanMarkdownLine(trimmed))
+		}
+	}
+	startSection("")
+
+	return doc.requireText()
+}
+
+var (
+	mdATXHeading      = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	mdSetextUnderline = regexp.MustCompile(`^(=+|-+)$`)
+	mdRule            = regexp.MustCompile(`^([-*_]\s*){3,}$`)
+	mdTableSeparator  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	mdLinkDefinition  = regexp.MustCompile(`^\[[^\]]+\]:\s+\S+`)
+
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\](\([^)]*\)|\[[^\]]*\])`)
+	mdEmphasis = regexp.MustCompile(`(\*\*|__|\*|_|~~)(\S(?:.*?\S)?)(\*\*|__|\*|_|~~)`)
+	mdCode     = regexp.MustCompile("`+([^`]+)`+")
+	mdHTMLTag  = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdListItem = regexp.MustCompile(`^([-*+]|\d+[.)])\s+(\[[ xX]\]\s+)?`)
+)
+
+// cleanMarkdownLine tira a marcação de bloco (citação, item de lista) e a inline.
+func cleanMarkdownLine(line string) string {
+	for strings.HasPrefix(line, ">") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, ">"))
+	}
+	if mdListItem.MatchString(line) {
+		line = "- " + mdListItem.ReplaceAllString(line, "")
+	}
+	return cleanInlineMarkdown(line)
+}
+
+func cleanInlineMarkdown(s string) string {
+	s = mdImage.ReplaceAllStringFunc(s, func(m string) string {
+		if alt := mdImage.FindStringSubmatch(m)[1]; alt != "" {
+			return "[Imagem: " + alt + "]"
+		}
+		return ""
+	})
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdCode.ReplaceAllString(s, "$1")
+	s = mdHTMLTag.ReplaceAllString(s, "")
+	// Ênfase aninhada (***texto***) precisa de mais de uma passada
+	for i := 0; i < 3; i++ {
+		s = stripEmphasis(s)
+	}
+	return strings.TrimSpace(s)
+}
+
+// stripEmphasis tira uma camada de ênfase. Sublinhados no meio de palavras (snake_case) não
+// são ênfase, como no CommonMark.
+func stripEmphasis(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mdEmphasis.FindAllStringSubmatchIndex(s, -1) {
+		open, inner, close := s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]]
+		if open != close || open[0] == '_' && (isWordByte(s, m[0]-1) || isWordByte(s, m[1])) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(inner)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isWordByte diz se s[i] existe e é letra ou dígito (bytes de UTF-8 multibyte contam como letra).
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := s[i]
+	return c >= 0x80 || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// skipFrontMatter descarta o bloco YAML entre "---" no começo do arquivo (Jekyll, Obsidian).
+func skipFrontMatter(lines []string) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
@@ -0,0 +1,54 @@
+package ingestion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMarkdown(t *testing.T) {
+	md := "---\ntitle: Resumo\ntags: [cardio]\n---\n" +
+		"Notas de **revisão** para a *prova*.\n\n" +
+		"# Insuficiência cardíaca #\n\n" +
+		"Ver [diretriz](https://example.com) e ![gráfico da FE](fe.png).\n" +
+		"Continua na mesma linha de parágrafo.\n\n" +
+		"> Citação com `código` e <b>HTML</b>.\n\n" +
+		"- [x] ***Dispneia***\n" +
+		"2. Edema\n\n" +
+		"---\n\n" +
+		"Tratamento\r\n" +
+		"==========\r\n\r\n" +
+		"| Droga | Classe |\n| --- | :---: |\n| Furosemida | Diurético |\n\n" +
+		"```go\nfunc main() {}\n```\n\n" +
+		"[diretriz]: https://example.com\n"
+
+	doc, err := ExtractMarkdown([]byte(md))
+	if err != nil {
+		t.Fatalf("ExtractMarkdown: %v", err)
+	}
+	want := []Section{
+		{Label: "", Text: "Notas de revisão para a prova."},
+		{Label: "Insuficiência cardíaca", Text: "Ver diretriz e [Imagem: gráfico da FE].\nContinua na mesma linha de parágrafo.\n\n" +
+			"Citação com código e HTML.\n\n- Dispneia\n- Edema"},
+		{Label: "Tratamento", Text: "| Droga | Classe |\n| Furosemida | Diurético |\n\nfunc main() {}"},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestCleanInlineMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"**negrito** e _itálico_":         "negrito e itálico",
+		"~~riscado~~":                     "riscado",
+		"snake_case_name":                 "snake_case_name",
+		"2 * 3 * 4":                       "2 * 3 * 4",
+		"[texto][ref]":                    "texto",
+		"![](decorativa.png) depois":      "depois",
+		"<span class=\"x\">dentro</span>": "dentro",
+	}
+	for in, want := range tests {
+		if got := cleanInlineMarkdown(in); got != want {
+			t.Errorf("cleanInlineMarkdown(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package ingestion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxPDFPages limita quantas páginas são lidas de um PDF.
+const MaxPDFPages = 2000
+
+// minTextRunes é o mínimo de letras e números para considerar que o documento tem texto;
+// abaixo disso um PDF é tratado como escaneado e os demais formatos, como vazios.
+const minTextRunes = 20
+
+// ExtractPDF lê o texto de cada página do PDF, em Go puro. Cada página com texto vira uma seção
+// rotulada "Página N". PDFs criptografados devolvem ErrEncrypted e PDFs sem texto (escaneados)
+// devolvem ErrScanned.
+func ExtractPDF(data []byte) (Document, error) {
+	file, err := parsePDF(data)
+	if err != nil {
+		return Document{}, err
+	}
+	if file.encrypted() {
+		return Document{}, ErrEncrypted
+	}
+
+	catalog := file.catalog()
+	if catalog == nil {
+		return Document{}, fmt.Errorf("%w: PDF catalog not found", ErrInvalidDocument)
+	}
+
+	pages := file.pages(catalog["Pages"])
+	if len(pages) == 0 {
+		return Document{}, fmt.Errorf("%w: PDF has no pages", ErrInvalidDocument)
+	}
+
+	fontCache := map[pdfRef]*pdfFont{}
+	var doc Document
+	textRunes := 0
+
+	for i, page := range pages {
+		e := &textExtractor{file: file, fontCache: fontCache}
+		e.run(file.pageContent(page.dict), file.loadResources(page.resources, fontCache))
+		if file.err != nil {
+			return Document{}, file.err
+		}
+
+		text := cleanText(e.out.String())
+		if text == "" {
+			continue
+		}
+		textRunes += countTextRunes(text)
+		doc.Sections = append(doc.Sections, Section{Label: fmt.Sprintf("Página %d", i+1), Text: text})
+	}
+
+	if textRunes < minTextRunes {
+		return Document{}, ErrScanned
+	}
+	return doc, nil
+}
+
+// pdfPage é uma folha da árvore de páginas, com os /Resources herdados já resolvidos.
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages percorre a árvore /Pages em ordem, herdando /Resources dos nós intermediários.
+func (f *pdfFile) pages(root any) []pdfPage {
+	var pages []pdfPage
+	visited := map[pdfRef]bool{}
+
+	var walk func(node any, inherited pdfDict)
+	walk = func(node any, inherited pdfDict) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		dict := f.dict(node)
+		if dict == nil || len(pages) >= MaxPDFPages {
+			return
+		}
+
+		resources := inherited
+		if r := f.dict(dict["Resources"]); r != nil {
+			resources = r
+		}
+
+		kids := f.array(dict["Kids"])
+		if dict["Type"] == pdfName("Page") || (kids == nil && dict["Contents"] != nil) {
+			pages = append(pages, pdfPage{dict: dict, resources: resources})
+			return
+		}
+		for _, kid := range kids {
+			walk(kid, resources)
+		}
+	}
+
+	walk(root, nil)
+	return pages
+}
+
+// pageContent junta os content streams da página (/Contents pode ser um stream ou um array).
+func (f *pdfFile) pageContent(page pdfDict) []byte {
+	var streams []any
+	switch v := f.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = []any{v}
+	case pdfArray:
+		streams = v
+	}
+
+	var b strings.Builder
+	for _, obj := range streams {
+		s, ok := f.resolve(obj).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := f.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
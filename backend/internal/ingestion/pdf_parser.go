@@ -0,0 +1,716 @@
+package ingestion
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// Leitor mínimo de PDF: só o necessário para achar as páginas e ler o texto delas.
+// Em vez de confiar na tabela xref (muitas vezes quebrada em PDFs gerados por ferramentas
+// simples), os objetos são localizados varrendo o arquivo por "N G obj"; objetos comprimidos
+// em object streams (/Type /ObjStm, PDF 1.5+) também são lidos.
+
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfArray   []any
+	pdfDict    map[string]any
+	pdfRef     struct{ num, gen int }
+	pdfKeyword string
+)
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+// Limites contra arquivos feitos para esgotar o servidor. maxDecodedStream vale para cada stream
+// descomprimido (zip bombs); maxDecodedTotal soma todos os streams decodificados do arquivo,
+// inclusive os lidos mais de uma vez, como o mesmo stream repetido em /Contents.
+const (
+	maxDecodedStream = 64 << 20
+	maxDecodedTotal  = 256 << 20
+)
+
+// maxObjectDepth limita o aninhamento de arrays e dicionários. parseDict e parseArray são
+// recursivos, e um arquivo com milhões de '[' estouraria a pilha, derrubando o processo.
+const maxObjectDepth = 64
+
+var (
+	errObjectTooDeep   = fmt.Errorf("%w: PDF objects are nested too deeply", ErrInvalidDocument)
+	errDecodedTooLarge = fmt.Errorf("%w: PDF content is too large once decompressed", ErrInvalidDocument)
+)
+
+var errUnsupportedFilter = errors.New("unsupported PDF stream filter")
+
+// pdfLexer percorre os tokens de um trecho de PDF ou de um content stream.
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int // arrays e dicionários abertos em parseObject
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos >= 0 && l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// next devolve o próximo token: número (int64 ou float64), pdfName, pdfString, pdfKeyword ou um
+// delimitador de estrutura ("<<", ">>", "[", "]") como pdfKeyword. io.EOF no fim dos dados.
+func (l *pdfLexer) next() (any, error) {
+	for {
+		l.skipSpace()
+		if l.pos < 0 || l.pos >= len(l.data) {
+			return nil, io.EOF
+		}
+
+		c := l.data[l.pos]
+		switch {
+		case c == '/':
+			return l.readName(), nil
+		case c == '(':
+			return l.readLiteralString(), nil
+		case c == '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				l.pos += 2
+				return pdfKeyword("<<"), nil
+			}
+			return l.readHexString(), nil
+		case c == '>':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+				l.pos += 2
+				return pdfKeyword(">>"), nil
+			}
+			l.pos++
+			continue
+		case c == '[' || c == ']' || c == '{' || c == '}':
+			l.pos++
+			return pdfKeyword(string(c)), nil
+		case c == ')':
+			// Parêntese solto: ignora
+			l.pos++
+			continue
+		}
+
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		word := string(l.data[start:l.pos])
+
+		if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(word, 64); err == nil {
+			return f, nil
+		}
+		return pdfKeyword(word), nil
+	}
+}
+
+func (l *pdfLexer) readName() pdfName {
+	l.pos++ // '/'
+	var b []byte
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := hex.DecodeString(string(l.data[l.pos+1 : l.pos+3])); err == nil {
+				b = append(b, v[0])
+				l.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		l.pos++
+	}
+	return pdfName(b)
+}
+
+func (l *pdfLexer) readLiteralString() pdfString {
+	l.pos++ // '('
+	depth := 1
+	var b []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Quebra de linha escapada: continua a string na próxima linha
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+func (l *pdfLexer) readHexString() pdfString {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b, _ := hex.DecodeString(string(digits))
+	return b
+}
+
+// parseObject lê um objeto completo a partir do token tok, resolvendo "N G R" em pdfRef.
+// Aninhamento além de maxObjectDepth devolve errObjectTooDeep.
+func (l *pdfLexer) parseObject(tok any) (any, error) {
+	switch t := tok.(type) {
+	case pdfKeyword:
+		switch t {
+		case "<<", "[":
+			if l.depth >= maxObjectDepth {
+				return nil, errObjectTooDeep
+			}
+			l.depth++
+			defer func() { l.depth-- }()
+			if t == "<<" {
+				return l.parseDict()
+			}
+			return l.parseArray()
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return t, nil
+	case int64:
+		// Pode ser o começo de uma referência "N G R"
+		save := l.pos
+		if gen, err := l.next(); err == nil {
+			if g, ok := gen.(int64); ok {
+				if r, err := l.next(); err == nil && r == pdfKeyword("R") {
+					return pdfRef{num: int(t), gen: int(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+		return t, nil
+	}
+	return tok, nil
+}
+
+func (l *pdfLexer) parseDict() (pdfDict, error) {
+	dict := pdfDict{}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return dict, err
+		}
+		if tok == pdfKeyword(">>") {
+			return dict, nil
+		}
+		key, ok := tok.(pdfName)
+		if !ok {
+			continue
+		}
+		valTok, err := l.next()
+		if err != nil {
+			return dict, err
+		}
+		if valTok == pdfKeyword(">>") {
+			return dict, nil
+		}
+		val, err := l.parseObject(valTok)
+		if err != nil {
+			return dict, err
+		}
+		dict[string(key)] = val
+	}
+}
+
+func (l *pdfLexer) parseArray() (pdfArray, error) {
+	var arr pdfArray
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return arr, err
+		}
+		if tok == pdfKeyword("]") {
+			return arr, nil
+		}
+		val, err := l.parseObject(tok)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, val)
+	}
+}
+
+// pdfFile guarda os objetos do arquivo indexados pelo número.
+type pdfFile struct {
+	objects  map[int]any
+	trailers []pdfDict
+
+	// decoded e operators somam o trabalho feito no arquivo todo (ver maxDecodedTotal e
+	// maxOperators). err guarda o primeiro limite estourado; depois dele nada mais é decodificado.
+	decoded   int
+	operators int
+	err       error
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+var trailerKeyword = regexp.MustCompile(`trailer\s*<<`)
+
+// parsePDF varre o arquivo atrás dos objetos e dos trailers.
+func parsePDF(data []byte) (*pdfFile, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing %%PDF header", ErrInvalidDocument)
+	}
+
+	f := &pdfFile{objects: map[int]any{}}
+
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		l := &pdfLexer{data: data, pos: m[1]}
+		tok, err := l.next()
+		if err != nil {
+			continue
+		}
+		obj, err := l.parseObject(tok)
+		if errors.Is(err, errObjectTooDeep) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+
+		if dict, ok := obj.(pdfDict); ok {
+			save := l.pos
+			if kw, err := l.next(); err == nil && kw == pdfKeyword("stream") {
+				obj = &pdfStream{dict: dict, raw: readStreamData(data, l.pos, dict)}
+			} else {
+				l.pos = save
+			}
+		}
+		if s, ok := obj.(*pdfStream); ok && s.dict["Type"] == pdfName("XRef") {
+			f.trailers = append(f.trailers, s.dict)
+		}
+		f.objects[num] = obj
+	}
+
+	for _, m := range trailerKeyword.FindAllIndex(data, -1) {
+		l := &pdfLexer{data: data, pos: m[1]}
+		if dict, err := l.parseDict(); err == nil {
+			f.trailers = append(f.trailers, dict)
+		}
+	}
+
+	f.loadObjectStreams()
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	if len(f.objects) == 0 {
+		return nil, fmt.Errorf("%w: no PDF objects found", ErrInvalidDocument)
+	}
+	return f, nil
+}
+
+// readStreamData lê os bytes entre "stream" e "endstream". Usa /Length quando é direto e
+// confere; senão procura o "endstream".
+func readStreamData(data []byte, pos int, dict pdfDict) []byte {
+	// A palavra "stream" é seguida de CRLF ou LF
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	if n, ok := dict["Length"].(int64); ok && n >= 0 && pos+int(n) <= len(data) {
+		end := pos + int(n)
+		rest := bytes.TrimLeft(data[end:min(len(data), end+32)], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return data[pos:end]
+		}
+	}
+
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return data[pos:]
+	}
+	return bytes.TrimRight(data[pos:pos+end], "\r\n")
+}
+
+// loadObjectStreams extrai os objetos comprimidos dentro de /Type /ObjStm.
+// Objetos já encontrados diretamente no arquivo não são sobrescritos.
+func (f *pdfFile) loadObjectStreams() {
+	for _, obj := range f.objects {
+		s, ok := obj.(*pdfStream)
+		if !ok || s.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := f.decodeStream(s)
+		if f.err != nil {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		n, _ := f.resolve(s.dict["N"]).(int64)
+		first, _ := f.resolve(s.dict["First"]).(int64)
+		if first <= 0 || int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:first]}
+		for i := int64(0); i < n; i++ {
+			numTok, err1 := header.next()
+			offTok, err2 := header.next()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			num, ok1 := numTok.(int64)
+			off, ok2 := offTok.(int64)
+			if !ok1 || !ok2 {
+				break
+			}
+			// O deslocamento é relativo a /First e precisa cair dentro do stream
+			if _, exists := f.objects[int(num)]; exists || off < 0 || off >= int64(len(data))-first {
+				continue
+			}
+
+			l := &pdfLexer{data: data, pos: int(first + off)}
+			tok, err := l.next()
+			if err != nil {
+				continue
+			}
+			val, err := l.parseObject(tok)
+			if errors.Is(err, errObjectTooDeep) {
+				f.err = err
+				return
+			}
+			if err == nil {
+				f.objects[int(num)] = val
+			}
+		}
+	}
+}
+
+// resolve segue referências até chegar a um objeto direto (nil se não existir).
+func (f *pdfFile) resolve(obj any) any {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = f.objects[ref.num]
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(obj any) pdfDict {
+	switch v := f.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+func (f *pdfFile) array(obj any) pdfArray {
+	if arr, ok := f.resolve(obj).(pdfArray); ok {
+		return arr
+	}
+	return nil
+}
+
+// encrypted diz se algum trailer aponta um dicionário /Encrypt.
+func (f *pdfFile) encrypted() bool {
+	for _, t := range f.trailers {
+		if _, ok := t["Encrypt"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// catalog devolve o dicionário raiz: o /Root do último trailer ou, sem trailer, o objeto /Type /Catalog.
+func (f *pdfFile) catalog() pdfDict {
+	for i := len(f.trailers) - 1; i >= 0; i-- {
+		if root := f.dict(f.trailers[i]["Root"]); root != nil {
+			return root
+		}
+	}
+	for _, obj := range f.objects {
+		if d, ok := obj.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+			return d
+		}
+	}
+	return nil
+}
+
+// decodeStream aplica os filtros do stream. Só os filtros de texto são suportados; imagens
+// (DCTDecode, JBIG2Decode, ...) devolvem errUnsupportedFilter. Passar de maxDecodedTotal
+// devolve errDecodedTooLarge, nesta e em todas as chamadas seguintes.
+func (f *pdfFile) decodeStream(s *pdfStream) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	data := s.raw
+
+	var filters []any
+	switch v := f.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{v}
+	case pdfArray:
+		filters = v
+	}
+	var params []any
+	switch v := f.resolve(s.dict["DecodeParms"]).(type) {
+	case pdfDict:
+		params = []any{v}
+	case pdfArray:
+		params = v
+	}
+
+	for i, filter := range filters {
+		var parms pdfDict
+		if i < len(params) {
+			parms = f.dict(params[i])
+		}
+
+		var err error
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data)
+			if err == nil {
+				data, err = unpredict(data, parms)
+			}
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data = decodeASCIIHex(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			return nil, errUnsupportedFilter
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.decoded += len(data)
+	if f.decoded > maxDecodedTotal {
+		f.err = errDecodedTooLarge
+		return nil, f.err
+	}
+	return data, nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, maxDecodedStream))
+	// Streams truncados são comuns; aproveita o que foi descomprimido
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+// unpredict desfaz os preditores PNG (Predictor >= 10) usados com FlateDecode.
+func unpredict(data []byte, parms pdfDict) ([]byte, error) {
+	predictor, _ := parms["Predictor"].(int64)
+	if predictor < 10 {
+		return data, nil
+	}
+	columns := int64(1)
+	if c, ok := parms["Columns"].(int64); ok && c > 0 {
+		columns = c
+	}
+	colors := int64(1)
+	if c, ok := parms["Colors"].(int64); ok && c > 0 {
+		colors = c
+	}
+	bpc := int64(8)
+	if b, ok := parms["BitsPerComponent"].(int64); ok && b > 0 {
+		bpc = b
+	}
+
+	bpp := int(max((colors*bpc+7)/8, 1))
+	rowLen := int((columns*colors*bpc + 7) / 8)
+	prev := make([]byte, rowLen)
+	var out []byte
+
+	for pos := 0; pos+1+rowLen <= len(data); pos += 1 + rowLen {
+		kind := data[pos]
+		row := append([]byte(nil), data[pos+1:pos+1+rowLen]...)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func decodeASCIIHex(data []byte) []byte {
+	l := &pdfLexer{data: append(append([]byte{'<'}, data...), '>')}
+	return l.readHexString()
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+
+	var out []byte
+	var group [5]byte
+	n := 0
+	flush := func(count int) {
+		var v uint32
+		for i := 0; i < 5; i++ {
+			v = v*85 + uint32(group[i]-'!')
+		}
+		b := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		out = append(out, b[:count]...)
+	}
+
+	for _, c := range data {
+		switch {
+		case isPDFWhitespace(c):
+			continue
+		case c == 'z' && n == 0:
+			out = append(out, 0, 0, 0, 0)
+			continue
+		case c < '!' || c > 'u':
+			return nil, fmt.Errorf("%w: invalid ASCII85 data", ErrInvalidDocument)
+		}
+		group[n] = c
+		n++
+		if n == 5 {
+			flush(4)
+			n = 0
+		}
+	}
+	if n > 0 {
+		for i := n; i < 5; i++ {
+			group[i] = 'u'
+		}
+		flush(n - 1)
+	}
+	return out, nil
+}
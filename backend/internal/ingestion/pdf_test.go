@@ -0,0 +1,384 @@
+package ingestion
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildPDF monta um PDF com os objetos dados, numerados a partir de 1, e um trailer que aponta
+// o objeto 1 como catálogo. Não há xref: o leitor acha os objetos varrendo o arquivo. Objetos
+// vazios só reservam o número (para os que estão dentro de um object stream).
+func buildPDF(objects ...string) []byte {
+	var b strings.Builder
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, obj := range objects {
+		if obj != "" {
+			fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+		}
+	}
+	b.WriteString("trailer\n<< /Size " + fmt.Sprint(len(objects)+1) + " /Root 1 0 R >>\n%%EOF\n")
+	return []byte(b.String())
+}
+
+// pdfStreamObject escreve um stream com /Length e o dicionário extra dado.
+func pdfStreamObject(dict string, data []byte) string {
+	return fmt.Sprintf("<< /Length %d %s >>\nstream\n%s\nendstream", len(data), dict, data)
+}
+
+func deflate(t testing.TB, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// simplePDF tem uma página por content stream, todas com a fonte Helvetica (WinAnsi) herdada
+// do nó /Pages.
+func simplePDF(contents ...string) []byte {
+	kids := make([]string, len(contents))
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // /Pages, preenchido abaixo
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	for i, content := range contents {
+		page := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", page+1),
+			pdfStreamObject("", []byte(content)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 3 0 R >> >> >>",
+		strings.Join(kids, " "), len(contents))
+	return buildPDF(objects...)
+}
+
+func TestExtractPDFLabelsPages(t *testing.T) {
+	data := simplePDF(
+		"BT /F1 12 Tf 72 720 Td (Insufici\\352ncia card\\355aca) Tj 0 -14 Td [(\\311 uma s\\355ndrome) -250 (cl\\355nica.)] TJ ET",
+		"q 100 0 0 100 0 0 cm Q",
+		"BT /F1 12 Tf 14 TL 72 720 Td (Tratamento:) Tj T* (diur\\351ticos e IECA.) Tj ET",
+	)
+
+	doc, err := ExtractPDF(data)
+	if err != nil {
+		t.Fatalf("ExtractPDF: %v", err)
+	}
+	want := []Section{
+		{Label: "Página 1", Text: "Insuficiência cardíaca\nÉ uma síndrome clínica."},
+		{Label: "Página 3", Text: "Tratamento:\ndiuréticos e IECA."},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractPDFRejections(t *testing.T) {
+	encrypted := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+	)
+	encrypted = bytes.Replace(encrypted, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt << /Filter /Standard /V 2 /R 3 >>"), 1)
+
+	// Só uma imagem na página: nada de texto
+	scanned := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /XObject << /Im1 5 0 R >> >> >>",
+		pdfStreamObject("", []byte("q 612 0 0 792 0 0 cm /Im1 Do Q")),
+		pdfStreamObject("/Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /DCTDecode", []byte{0xff, 0xd8, 0xff, 0xd9}),
+	)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"encrypted", encrypted, ErrEncrypted},
+		{"scanned", scanned, ErrScanned},
+		{"little text", simplePDF("BT /F1 12 Tf (p. 1) Tj ET"), ErrScanned},
+		{"not a pdf", []byte("GIF89a"), ErrInvalidDocument},
+		{"no catalog", buildPDF("<< /Type /Font >>"), ErrInvalidDocument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExtractPDF(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// pngEncode aplica o filtro PNG kind a cada linha de data, como um gerador de PDF faria antes
+// do FlateDecode com /Predictor >= 10.
+func pngEncode(data []byte, rowLen, bpp int, kinds []byte) []byte {
+	var out []byte
+	prev := make([]byte, rowLen)
+	for r := 0; r*rowLen < len(data); r++ {
+		row := data[r*rowLen : (r+1)*rowLen]
+		kind := kinds[r%len(kinds)]
+		out = append(out, kind)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 0:
+				out = append(out, row[i])
+			case 1:
+				out = append(out, row[i]-left)
+			case 2:
+				out = append(out, row[i]-up)
+			case 3:
+				out = append(out, row[i]-byte((int(left)+int(up))/2))
+			case 4:
+				out = append(out, row[i]-paeth(left, up, upLeft))
+			}
+		}
+		prev = row
+	}
+	return out
+}
+
+func TestUnpredictPNG(t *testing.T) {
+	raw := []byte("linha um de texto, linha dois!!, mais uma linha..., e a última aqui")
+	raw = raw[:len(raw)/8*8]
+	tests := []struct {
+		name  string
+		parms pdfDict
+		bpp   int
+	}{
+		{"gray", pdfDict{"Predictor": int64(12), "Columns": int64(8)}, 1},
+		{"rgb", pdfDict{"Predictor": int64(15), "Columns": int64(2), "Colors": int64(4)}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := pngEncode(raw, 8, tt.bpp, []byte{0, 1, 2, 3, 4})
+			got, err := unpredict(encoded, tt.parms)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, raw) {
+				t.Errorf("unpredict = %q, want %q", got, raw)
+			}
+		})
+	}
+
+	if got, _ := unpredict(raw, pdfDict{"Predictor": int64(1)}); !bytes.Equal(got, raw) {
+		t.Error("predictor 1 should leave the data untouched")
+	}
+}
+
+// objStmPDF guarda a página e a fonte num object stream comprimido com FlateDecode e preditor
+// PNG, como fazem os geradores de PDF 1.5+.
+func objStmPDF(t *testing.T, content string) []byte {
+	t.Helper()
+	objs := []string{
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	var header, body strings.Builder
+	for i, obj := range objs {
+		fmt.Fprintf(&header, "%d %d ", i+2, body.Len())
+		body.WriteString(obj + "\n")
+	}
+	stm := header.String() + body.String()
+	first := len(header.String())
+
+	padded := []byte(stm)
+	for len(padded)%8 != 0 {
+		padded = append(padded, ' ')
+	}
+	encoded := deflate(t, pngEncode(padded, 8, 1, []byte{2}))
+
+	return buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", "", "", // 2 a 4 estão no object stream
+		pdfStreamObject(fmt.Sprintf("/Type /ObjStm /N 3 /First %d /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 8 >>", first), encoded),
+		pdfStreamObject("/Filter [/ASCIIHexDecode /FlateDecode]", []byte(fmt.Sprintf("%x>", deflate(t, []byte(content))))),
+	)
+}
+
+func TestExtractPDFObjectStream(t *testing.T) {
+	doc, err := ExtractPDF(objStmPDF(t, "BT /F1 11 Tf 50 700 Td (Texto dentro de um object stream comprimido.) Tj ET"))
+	if err != nil {
+		t.Fatalf("ExtractPDF: %v", err)
+	}
+	want := []Section{{Label: "Página 1", Text: "Texto dentro de um object stream comprimido."}}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractPDFToUnicode(t *testing.T) {
+	// Fonte composta (Identity-H) com códigos de 2 bytes: maiúsculas e acentos em bfchar,
+	// minúsculas num bfrange incrementado e a ligadura "fi" no bfrange com array
+	cmap := `/CIDInit /ProcSet findresource begin 12 dict begin begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+4 beginbfchar <0001> <0043> <0002> <00E7> <0003> <0020> <0004> <002E> endbfchar
+2 beginbfrange <0041> <005A> <0061> <0100> <0101> [<FB01> <00E3>] endbfrange
+endcmap CMapName currentdict /CMap defineresource pop end end`
+	codes := map[rune]string{'C': "0001", 'ç': "0002", ' ': "0003", '.': "0004", 'ﬁ': "0100", 'ã': "0101"}
+	encode := func(text string) string {
+		var b strings.Builder
+		for _, r := range text {
+			if code, ok := codes[r]; ok {
+				b.WriteString(code)
+			} else {
+				fmt.Fprintf(&b, "%04X", 0x41+r-'a')
+			}
+		}
+		return "<" + b.String() + ">"
+	}
+
+	content := "BT /F1 12 Tf 72 700 Td " + encode("Coração e ﬁbrilação atrial.") + " Tj 0 -20 Td " +
+		"/F2 12 Tf <00410042> Tj /F1 12 Tf " + encode("Ciclo do coração.") + " Tj ET"
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R /F2 7 0 R >> >> >>",
+		pdfStreamObject("", []byte(content)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /ABCDEF+Arial /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		pdfStreamObject("", []byte(cmap)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Sem+Mapa /Encoding /Identity-H >>",
+	)
+
+	doc, err := ExtractPDF(data)
+	if err != nil {
+		t.Fatalf("ExtractPDF: %v", err)
+	}
+	// A fonte composta sem ToUnicode (F2) não tem como ser decodificada e fica de fora
+	want := []Section{{Label: "Página 1", Text: "Coração e ﬁbrilação atrial.\nCiclo do coração."}}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestParseCMap(t *testing.T) {
+	cm := parseCMap([]byte(`begincodespacerange <00> <7F> <8000> <FFFF> endcodespacerange
+1 beginbfchar <41> <0042> endbfchar
+1 beginbfrange <8000> <8002> <D835DC00> endbfrange`))
+
+	if !reflect.DeepEqual(cm.codeLens, []int{1, 2}) {
+		t.Errorf("codeLens = %v, want [1 2]", cm.codeLens)
+	}
+	// Códigos de 1 e 2 bytes misturados; o destino em UTF-16 com par substituto é incrementado
+	if got, want := cm.decode([]byte{0x41, 0x80, 0x01, 0x7e, 0x80, 0x02}), "B𝐁𝐂"; got != want {
+		t.Errorf("decode = %q, want %q", got, want)
+	}
+}
+
+func TestExtractPDFMaliciousInputs(t *testing.T) {
+	deepArray := append([]byte("%PDF-1.7\n1 0 obj\n"), bytes.Repeat([]byte("["), 1<<20)...)
+	deepContent := simplePDF("BT /F1 12 Tf (Texto suficiente antes do problema) Tj ET " + strings.Repeat("[", 1<<20))
+	strayParens := simplePDF("BT /F1 12 Tf (Texto suficiente mesmo com lixo depois) Tj ET " + strings.Repeat(")", 1<<20))
+
+	// Deslocamento negativo no cabeçalho do object stream
+	negativeOffset := buildPDF(
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		pdfStreamObject("/Type /ObjStm /N 2 /First 10", []byte("3 -20 4 99 << /Type /Pages >>")),
+	)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"deeply nested object", deepArray, ErrInvalidDocument},
+		{"deeply nested content", deepContent, ErrInvalidDocument},
+		{"negative object stream offset", negativeOffset, ErrInvalidDocument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExtractPDF(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Parênteses soltos são ignorados sem recursão
+	doc, err := ExtractPDF(strayParens)
+	if err != nil {
+		t.Fatalf("stray parentheses: %v", err)
+	}
+	if got := doc.Text(); got != "[Página 1]\nTexto suficiente mesmo com lixo depois" {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestLexerRejectsNegativePosition(t *testing.T) {
+	l := &pdfLexer{data: []byte("1 0 obj"), pos: -15}
+	if tok, err := l.next(); err == nil {
+		t.Errorf("next at a negative position = %v, want an error", tok)
+	}
+}
+
+// runFirstPage interpreta a primeira página de um arquivo já lido, como ExtractPDF faz.
+func runFirstPage(file *pdfFile) {
+	page := file.pages(file.catalog()["Pages"])[0]
+	fontCache := map[pdfRef]*pdfFont{}
+	e := &textExtractor{file: file, fontCache: fontCache}
+	e.run(file.pageContent(page.dict), file.loadResources(page.resources, fontCache))
+}
+
+// Os testes de limite partem de um arquivo com quase todo o orçamento já gasto, para não
+// precisar descomprimir centenas de MB nem interpretar milhões de operadores.
+
+func TestExtractPDFDecodeBudget(t *testing.T) {
+	// Um stream pequeno que descomprime em 1 MB, repetido em /Contents
+	bomb := deflate(t, bytes.Repeat([]byte(" "), 1<<20))
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents ["+strings.Repeat("4 0 R ", 64)+"] >>",
+		pdfStreamObject("/Filter /FlateDecode", bomb),
+	)
+
+	file, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.decoded = maxDecodedTotal - 32<<20
+	runFirstPage(file)
+	if !errors.Is(file.err, ErrInvalidDocument) {
+		t.Errorf("err = %v, want ErrInvalidDocument", file.err)
+	}
+	if file.decoded > maxDecodedTotal+1<<20 {
+		t.Errorf("kept decoding after the budget: %d bytes", file.decoded)
+	}
+}
+
+func TestExtractPDFOperatorBudget(t *testing.T) {
+	// Form XObject que chama a si mesmo 16 vezes: 16^8 execuções até maxFormDepth
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /XObject << /X 5 0 R >> >> >>",
+		pdfStreamObject("", []byte("/X Do")),
+		pdfStreamObject("/Type /XObject /Subtype /Form /BBox [0 0 10 10] /Resources << /XObject << /X 5 0 R >> >>", []byte(strings.Repeat("/X Do ", 16))),
+	)
+
+	file, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.operators = maxOperators - 100_000
+	runFirstPage(file)
+	if !errors.Is(file.err, ErrInvalidDocument) {
+		t.Errorf("err = %v, want ErrInvalidDocument", file.err)
+	}
+}
@@ -0,0 +1,460 @@
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// pdfFont decodifica os bytes das strings de texto de uma fonte.
+type pdfFont struct {
+	toUnicode *cmap
+	// twoByte indica fonte composta (Type0) sem ToUnicode: sem tabela não dá para decodificar
+	twoByte bool
+}
+
+func (f *pdfFont) decode(s []byte) string {
+	if f != nil && f.toUnicode != nil {
+		return f.toUnicode.decode(s)
+	}
+	if f != nil && f.twoByte {
+		return ""
+	}
+	// Fontes simples sem ToUnicode: assume WinAnsiEncoding, que cobre os acentos do português
+	var b strings.Builder
+	for _, c := range s {
+		if r, ok := winAnsiHigh[c]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// winAnsiHigh são os códigos 0x80-0x9F do WinAnsiEncoding que diferem do Latin-1.
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+	0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// cmap é uma tabela ToUnicode: código (1 a 4 bytes) para texto.
+type cmap struct {
+	codeLens []int // tamanhos de código declarados em codespacerange, em ordem crescente
+	chars    map[string]string
+}
+
+// parseCMap lê as seções codespacerange, bfchar e bfrange de um CMap ToUnicode.
+func parseCMap(data []byte) *cmap {
+	cm := &cmap{chars: map[string]string{}}
+	l := &pdfLexer{data: data}
+
+	var operands []any
+	for {
+		tok, err := l.next()
+		if err != nil {
+			break
+		}
+		obj, _ := l.parseObject(tok)
+		kw, isKeyword := obj.(pdfKeyword)
+		if !isKeyword {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch kw {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].(pdfString); ok && len(lo) > 0 {
+					cm.addCodeLen(len(lo))
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					cm.chars[string(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				cm.addRange(lo, hi, operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+
+	if len(cm.codeLens) == 0 {
+		cm.addCodeLen(1)
+	}
+	return cm
+}
+
+func (cm *cmap) addCodeLen(n int) {
+	for _, l := range cm.codeLens {
+		if l == n {
+			return
+		}
+	}
+	cm.codeLens = append(cm.codeLens, n)
+	for i := len(cm.codeLens) - 1; i > 0 && cm.codeLens[i] < cm.codeLens[i-1]; i-- {
+		cm.codeLens[i], cm.codeLens[i-1] = cm.codeLens[i-1], cm.codeLens[i]
+	}
+}
+
+// maxRangeSize evita laços enormes com CMaps malformados.
+const maxRangeSize = 1 << 16
+
+func (cm *cmap) addRange(lo, hi pdfString, dst any) {
+	start, end := bytesToInt(lo), bytesToInt(hi)
+	if end < start || end-start > maxRangeSize {
+		return
+	}
+
+	switch d := dst.(type) {
+	case pdfString:
+		// O destino é incrementado a cada código (o último caractere UTF-16)
+		base := utf16.Decode(bytesToUTF16(d))
+		for code := start; code <= end; code++ {
+			text := append([]rune(nil), base...)
+			if len(text) > 0 {
+				text[len(text)-1] += rune(code - start)
+			}
+			cm.chars[string(intToBytes(code, len(lo)))] = string(text)
+		}
+	case pdfArray:
+		for i, item := range d {
+			s, ok := item.(pdfString)
+			if !ok || start+i > end {
+				continue
+			}
+			cm.chars[string(intToBytes(start+i, len(lo)))] = utf16BE(s)
+		}
+	}
+}
+
+func (cm *cmap) decode(s []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, n := range cm.codeLens {
+			if i+n > len(s) {
+				break
+			}
+			if text, ok := cm.chars[string(s[i:i+n])]; ok {
+				b.WriteString(text)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// Código sem mapeamento: pula com o menor tamanho declarado
+			i += cm.codeLens[0]
+		}
+	}
+	return b.String()
+}
+
+func bytesToInt(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n<<8 | int(c)
+	}
+	return n
+}
+
+func intToBytes(n, size int) []byte {
+	b := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+	return b
+}
+
+func bytesToUTF16(b []byte) []uint16 {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return u
+}
+
+func utf16BE(b []byte) string {
+	return string(utf16.Decode(bytesToUTF16(b)))
+}
+
+// pageResources guarda as fontes e XObjects de uma página (ou de um Form XObject).
+type pageResources struct {
+	fonts    map[string]*pdfFont
+	xobjects pdfDict
+}
+
+func (f *pdfFile) loadResources(resources pdfDict, fontCache map[pdfRef]*pdfFont) pageResources {
+	res := pageResources{fonts: map[string]*pdfFont{}, xobjects: f.dict(resources["XObject"])}
+
+	for name, ref := range f.dict(resources["Font"]) {
+		if r, ok := ref.(pdfRef); ok {
+			if font, ok := fontCache[r]; ok {
+				res.fonts[name] = font
+				continue
+			}
+		}
+
+		fontDict := f.dict(ref)
+		font := &pdfFont{twoByte: fontDict["Subtype"] == pdfName("Type0")}
+		if s, ok := f.resolve(fontDict["ToUnicode"]).(*pdfStream); ok {
+			if data, err := f.decodeStream(s); err == nil {
+				font.toUnicode = parseCMap(data)
+			}
+		}
+
+		res.fonts[name] = font
+		if r, ok := ref.(pdfRef); ok {
+			fontCache[r] = font
+		}
+	}
+	return res
+}
+
+// textExtractor interpreta um content stream e junta o texto mostrado, quebrando linha quando a
+// posição vertical muda e pondo espaço em saltos horizontais.
+type textExtractor struct {
+	file      *pdfFile
+	fontCache map[pdfRef]*pdfFont
+	out       strings.Builder
+	depth     int
+
+	font  *pdfFont
+	tm    [6]float64 // matriz de texto
+	tlm   [6]float64 // matriz do começo da linha
+	lead  float64    // TL
+	lastY float64
+	shown bool // já mostrou texto nesta página
+}
+
+var identity = [6]float64{1, 0, 0, 1, 0, 0}
+
+// maxFormDepth limita a recursão em Form XObjects que se referenciam.
+const maxFormDepth = 8
+
+// maxOperators limita os operadores interpretados no arquivo todo. Sem ele, um Form XObject que
+// chama Do em si mesmo várias vezes cresce exponencialmente até maxFormDepth.
+const maxOperators = 20_000_000
+
+var errTooManyOperators = fmt.Errorf("%w: PDF content is too complex", ErrInvalidDocument)
+
+// run interpreta content. Ao estourar um limite, grava o erro em e.file.err e para.
+func (e *textExtractor) run(content []byte, res pageResources) {
+	l := &pdfLexer{data: content}
+	var operands []any
+
+	for e.file.err == nil {
+		tok, err := l.next()
+		if err != nil {
+			return
+		}
+		obj, err := l.parseObject(tok)
+		if errors.Is(err, errObjectTooDeep) {
+			e.file.err = err
+			return
+		}
+		op, isOp := obj.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+		if e.file.operators++; e.file.operators > maxOperators {
+			e.file.err = errTooManyOperators
+			return
+		}
+
+		switch op {
+		case "BI":
+			// Imagem inline: pula até o EI sem interpretar os bytes
+			if end := strings.Index(string(content[l.pos:]), "EI"); end >= 0 {
+				l.pos += end + 2
+			} else {
+				return
+			}
+		case "BT":
+			e.tm, e.tlm = identity, identity
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					e.font = res.fonts[string(name)]
+				}
+			}
+		case "TL":
+			e.lead = number(operands, 0)
+		case "Td", "TD":
+			tx, ty := number(operands, 0), number(operands, 1)
+			if op == "TD" {
+				e.lead = -ty
+			}
+			e.translate(tx, ty)
+		case "Tm":
+			if len(operands) >= 6 {
+				for i := range e.tm {
+					e.tm[i] = number(operands, i)
+				}
+				e.tlm = e.tm
+			}
+		case "T*":
+			e.translate(0, -e.lead)
+		case "Tj":
+			if s, ok := last(operands).(pdfString); ok {
+				e.show(s)
+			}
+		case "'", "\"":
+			e.translate(0, -e.lead)
+			if s, ok := last(operands).(pdfString); ok {
+				e.show(s)
+			}
+		case "TJ":
+			if arr, ok := last(operands).(pdfArray); ok {
+				for _, item := range arr {
+					switch v := item.(type) {
+					case pdfString:
+						e.show(v)
+					case int64, float64:
+						// Ajustes grandes de espaçamento (em milésimos de em) são espaços entre palavras
+						if number(pdfArray{v}, 0) < -200 {
+							e.space()
+						}
+					}
+				}
+			}
+		case "Do":
+			if name, ok := last(operands).(pdfName); ok {
+				e.doXObject(string(name), res)
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+func (e *textExtractor) translate(tx, ty float64) {
+	// tlm = [1 0 0 1 tx ty] × tlm
+	e.tlm[4] += tx*e.tlm[0] + ty*e.tlm[2]
+	e.tlm[5] += tx*e.tlm[1] + ty*e.tlm[3]
+	e.tm = e.tlm
+	if ty == 0 && tx != 0 {
+		e.space()
+	}
+}
+
+func (e *textExtractor) show(s []byte) {
+	text := e.font.decode(s)
+	if text == "" {
+		return
+	}
+
+	y := e.tm[5]
+	if e.shown && math.Abs(y-e.lastY) > 1 {
+		e.newline()
+	}
+	e.lastY = y
+	e.shown = true
+	e.out.WriteString(text)
+}
+
+func (e *textExtractor) space() {
+	if e.out.Len() == 0 {
+		return
+	}
+	s := e.out.String()
+	if r := s[len(s)-1]; r != ' ' && r != '\n' {
+		e.out.WriteByte(' ')
+	}
+}
+
+func (e *textExtractor) newline() {
+	if e.out.Len() > 0 {
+		e.out.WriteByte('\n')
+	}
+}
+
+func (e *textExtractor) doXObject(name string, res pageResources) {
+	s, ok := e.file.resolve(res.xobjects[name]).(*pdfStream)
+	if !ok {
+		return
+	}
+	if s.dict["Subtype"] != pdfName("Form") || e.depth >= maxFormDepth {
+		return
+	}
+
+	data, err := e.file.decodeStream(s)
+	if err != nil {
+		return
+	}
+	formRes := res
+	if r := e.file.dict(s.dict["Resources"]); r != nil {
+		formRes = e.file.loadResources(r, e.fontCache)
+	}
+
+	// O form tem seu próprio BT/ET; guarda o estado de texto da página
+	savedTm, savedTlm, savedFont := e.tm, e.tlm, e.font
+	e.depth++
+	e.run(data, formRes)
+	e.depth--
+	e.tm, e.tlm, e.font = savedTm, savedTlm, savedFont
+}
+
+func number(operands []any, i int) float64 {
+	if i >= len(operands) {
+		return 0
+	}
+	switch v := operands[i].(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func last(operands []any) any {
+	if len(operands) == 0 {
+		return nil
+	}
+	return operands[len(operands)-1]
+}
+
+// cleanText normaliza o texto de uma página: tira caracteres de controle, junta espaços
+// repetidos e remove linhas vazias.
+func cleanText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n':
+			return r
+		case r == ' ' || unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r) || r == unicode.ReplacementChar:
+			return -1
+		}
+		return r
+	}, s)
+
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,173 @@
+package ingestion
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExtractPPTX lê uma apresentação do PowerPoint na ordem dos slides. Cada slide vira uma seção
+// "Slide N" (com o título do slide, se houver) contendo o texto das caixas e, depois, as notas
+// do apresentador, onde costuma estar a explicação da aula.
+func ExtractPPTX(data []byte) (Document, error) {
+	z, err := openZip(data)
+	if err != nil {
+		return Document{}, err
+	}
+
+	slides, err := pptxSlideOrder(z)
+	if err != nil {
+		return Document{}, err
+	}
+
+	var doc Document
+	for i, slidePath := range slides {
+		body, ok, err := z.read(slidePath)
+		if err != nil {
+			return Document{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		var paragraphs []string
+		if err := ooxmlParagraphs(body, func(text, _ string) {
+			paragraphs = append(paragraphs, text)
+		}); err != nil {
+			return Document{}, err
+		}
+
+		notes, err := pptxNotes(z, slidePath)
+		if err != nil {
+			return Document{}, err
+		}
+		if len(paragraphs) == 0 && notes == "" {
+			continue
+		}
+
+		label := fmt.Sprintf("Slide %d", i+1)
+		if title := pptxTitle(body); title != "" {
+			label += ": " + title
+			// O título já está no rótulo
+			if len(paragraphs) > 0 && paragraphs[0] == title {
+				paragraphs = paragraphs[1:]
+			}
+		}
+		text := strings.Join(paragraphs, "\n")
+		if notes != "" {
+			text = strings.TrimSpace(text + "\n\nNotas do apresentador:\n" + notes)
+		}
+		doc.Sections = append(doc.Sections, Section{Label: label, Text: text})
+	}
+
+	return doc.requireText()
+}
+
+// pptxSlideOrder devolve os caminhos dos slides na ordem de ppt/presentation.xml (sldIdLst),
+// que pode ser diferente da numeração dos arquivos.
+func pptxSlideOrder(z *zipDocument) ([]string, error) {
+	const presentation = "ppt/presentation.xml"
+	data, ok, err := z.read(presentation)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidDocument, presentation)
+	}
+	rels, err := z.relationships(presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "sldId" {
+			continue
+		}
+		// O atributo r:id (com namespace) aponta o slide; o id sem namespace é só numérico
+		for _, a := range start.Attr {
+			if a.Name.Local == "id" && a.Name.Space != "" {
+				if rel, ok := rels[a.Value]; ok {
+					slides = append(slides, rel.Target)
+				}
+			}
+		}
+	}
+	return slides, nil
+}
+
+// pptxShapes é o necessário de um slide ou de uma página de notas: as formas com o tipo de
+// placeholder e o texto de cada parágrafo.
+type pptxShapes struct {
+	Shapes []struct {
+		Placeholder struct {
+			Type string `xml:"type,attr"`
+		} `xml:"nvSpPr>nvPr>ph"`
+		Paragraphs []struct {
+			Runs []string `xml:"r>t"`
+		} `xml:"txBody>p"`
+	} `xml:"cSld>spTree>sp"`
+}
+
+// text junta os parágrafos das formas cujo placeholder é de um dos tipos pedidos.
+func (s pptxShapes) text(types ...string) string {
+	var paragraphs []string
+	for _, shape := range s.Shapes {
+		wanted := false
+		for _, t := range types {
+			wanted = wanted || shape.Placeholder.Type == t
+		}
+		if !wanted {
+			continue
+		}
+		for _, p := range shape.Paragraphs {
+			if text := strings.TrimSpace(strings.Join(p.Runs, "")); text != "" {
+				paragraphs = append(paragraphs, text)
+			}
+		}
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+func pptxTitle(slide []byte) string {
+	var shapes pptxShapes
+	if xml.Unmarshal(slide, &shapes) != nil {
+		return ""
+	}
+	return strings.ReplaceAll(shapes.text("title", "ctrTitle"), "\n", " ")
+}
+
+// pptxNotes devolve as notas do apresentador do slide: só o placeholder "body" da página de
+// notas, sem a miniatura do slide e o número da página.
+func pptxNotes(z *zipDocument, slidePath string) (string, error) {
+	rels, err := z.relationships(slidePath)
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range rels {
+		if !strings.HasSuffix(rel.Type, "/notesSlide") {
+			continue
+		}
+		data, ok, err := z.read(rel.Target)
+		if err != nil || !ok {
+			return "", err
+		}
+		var shapes pptxShapes
+		if err := xml.Unmarshal(data, &shapes); err != nil {
+			return "", fmt.Errorf("%w: %s: %v", ErrInvalidDocument, rel.Target, err)
+		}
+		return shapes.text("body"), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,80 @@
+package ingestion
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	pptxNamespaces = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" ` +
+		`xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	relsNamespace     = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+	slideRelType      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide"
+	notesSlideRelType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide"
+)
+
+// pptxShape é uma caixa de texto; placeholder vazio é uma caixa comum.
+func pptxShape(placeholder string, paragraphs ...string) string {
+	s := `<p:sp><p:nvSpPr><p:cNvPr id="2" name="Caixa"/><p:cNvSpPr/><p:nvPr>`
+	if placeholder != "" {
+		s += `<p:ph type="` + placeholder + `"/>`
+	}
+	s += `</p:nvPr></p:nvSpPr><p:txBody>`
+	for _, p := range paragraphs {
+		s += `<a:p><a:r><a:t>` + p + `</a:t></a:r></a:p>`
+	}
+	return s + `</p:txBody></p:sp>`
+}
+
+func pptxSlide(root string, shapes ...string) string {
+	s := `<p:` + root + ` ` + pptxNamespaces + `><p:cSld><p:spTree>`
+	for _, shape := range shapes {
+		s += shape
+	}
+	return s + `</p:spTree></p:cSld></p:` + root + `>`
+}
+
+func TestExtractPPTX(t *testing.T) {
+	// A ordem de apresentação (sldIdLst) inverte a numeração dos arquivos, e as notas do
+	// slide1.xml estão num arquivo com outro número
+	data := buildZip(t,
+		zipEntry{"ppt/presentation.xml", `<p:presentation ` + pptxNamespaces + `><p:sldIdLst>` +
+			`<p:sldId id="256" r:id="rId3"/><p:sldId id="257" r:id="rId2"/></p:sldIdLst></p:presentation>`},
+		zipEntry{"ppt/_rels/presentation.xml.rels", `<Relationships ` + relsNamespace + `>` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideMaster" Target="slideMasters/slideMaster1.xml"/>` +
+			`<Relationship Id="rId2" Type="` + slideRelType + `" Target="slides/slide1.xml"/>` +
+			`<Relationship Id="rId3" Type="` + slideRelType + `" Target="slides/slide2.xml"/></Relationships>`},
+		zipEntry{"ppt/slides/slide2.xml", pptxSlide("sld",
+			pptxShape("title", "Anatomia"),
+			pptxShape("body", "O coração tem quatro câmaras.", "Átrios e ventrículos."))},
+		zipEntry{"ppt/slides/slide1.xml", pptxSlide("sld",
+			pptxShape("", "Fisiologia do ciclo cardíaco e pressões."))},
+		zipEntry{"ppt/slides/_rels/slide1.xml.rels", `<Relationships ` + relsNamespace + `>` +
+			`<Relationship Id="rId2" Type="` + notesSlideRelType + `" Target="../notesSlides/notesSlide7.xml"/></Relationships>`},
+		zipEntry{"ppt/notesSlides/notesSlide7.xml", pptxSlide("notes",
+			pptxShape("sldImg"),
+			pptxShape("body", "Explicar a sístole com calma."),
+			pptxShape("sldNum", "2"))},
+	)
+
+	doc, err := ExtractPPTX(data)
+	if err != nil {
+		t.Fatalf("ExtractPPTX: %v", err)
+	}
+	want := []Section{
+		{Label: "Slide 1: Anatomia", Text: "O coração tem quatro câmaras.\nÁtrios e ventrículos."},
+		{Label: "Slide 2", Text: "Fisiologia do ciclo cardíaco e pressões.\n\nNotas do apresentador:\nExplicar a sístole com calma."},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractPPTXWithoutPresentation(t *testing.T) {
+	data := buildZip(t, zipEntry{"ppt/slides/slide1.xml", pptxSlide("sld", pptxShape("", "texto solto"))})
+	if _, err := ExtractPPTX(data); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("err = %v, want ErrInvalidDocument", err)
+	}
+}
@@ -0,0 +1,201 @@
+package ingestion
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Agrupamento das falas em seções: cada seção fecha no fim da frase depois de
+// transcriptSectionSpan ou transcriptSectionChars; sem pontuação (legendas automáticas),
+// fecha no meio da frase depois do dobro disso.
+const (
+	transcriptSectionSpan  = 60 * time.Second
+	transcriptSectionChars = 1200
+)
+
+// ExtractSubtitles lê legendas SRT ou WebVTT de uma aula gravada. Os tempos e a marcação são
+// descartados, os fragmentos das legendas são juntados em frases (inclusive as legendas
+// automáticas, que repetem a linha anterior em cada fragmento) e as frases são agrupadas em
+// seções de cerca de um minuto, rotuladas com o tempo em que começam ("00:12:34"), que servem
+// de âncora para os cards gerados de cada trecho.
+func ExtractSubtitles(data []byte) (Document, error) {
+	cues := parseCues(string(data))
+	if len(cues) == 0 {
+		return Document{}, fmt.Errorf("%w: no subtitle cues found", ErrInvalidDocument)
+	}
+
+	var words []timedWord
+	for _, c := range cues {
+		words = appendCue(words, c)
+	}
+
+	doc := Document{Sections: transcriptSections(words)}
+	return doc.requireText()
+}
+
+// cue é uma legenda: o tempo em que aparece e o texto, já sem marcação.
+type cue struct {
+	start time.Duration
+	text  string
+}
+
+var (
+	cueTiming  = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[,.]\d{1,3})\s*-->`)
+	cueTag     = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+	soundLabel = regexp.MustCompile(`\[[^\]]*\]|\([A-Za-zÀ-ú ]+\)|♪+`)
+)
+
+// parseCues lê os blocos separados por linha em branco. Blocos sem "-->" (cabeçalho WEBVTT,
+// NOTE, STYLE, REGION) são ignorados; o número que precede o tempo no SRT e os IDs do VTT
+// ficam antes da linha de tempo e também são descartados.
+func parseCues(s string) []cue {
+	s = strings.TrimPrefix(s, "\ufeff")
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+
+	var cues []cue
+	for _, block := range strings.Split(s, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		for i, line := range lines {
+			m := cueTiming.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, ok := parseCueTime(m[1])
+			if !ok {
+				break
+			}
+			text := strings.Join(lines[i+1:], " ")
+			text = html.UnescapeString(cueTag.ReplaceAllString(text, ""))
+			text = collapseSpaces(soundLabel.ReplaceAllString(text, " "))
+			// Falas marcadas com "-" (troca de interlocutor) viram frases comuns
+			text = strings.TrimSpace(strings.TrimPrefix(text, "- "))
+			if text != "" {
+				cues = append(cues, cue{start: start, text: text})
+			}
+			break
+		}
+	}
+	return cues
+}
+
+// parseCueTime aceita "HH:MM:SS,mmm" (SRT) e "HH:MM:SS.mmm" ou "MM:SS.mmm" (VTT).
+func parseCueTime(s string) (time.Duration, bool) {
+	s = strings.Replace(s, ",", ".", 1)
+	main, frac, _ := strings.Cut(s, ".")
+	parts := strings.Split(main, ":")
+
+	var total time.Duration
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, false
+		}
+		total = total*60 + time.Duration(n)
+	}
+	total *= time.Second
+
+	if frac != "" {
+		ms, err := strconv.Atoi((frac + "00")[:3])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, true
+}
+
+// timedWord é uma palavra da transcrição com o tempo da legenda em que apareceu primeiro.
+type timedWord struct {
+	text string
+	at   time.Duration
+}
+
+// maxCueOverlap limita quantas palavras do fim da transcrição são comparadas com o começo da
+// próxima legenda.
+const maxCueOverlap = 40
+
+// appendCue acrescenta as palavras da legenda que ainda não estão no fim da transcrição.
+// Legendas automáticas repetem a linha anterior e acrescentam algumas palavras; a maior
+// sobreposição entre o fim do que já foi lido e o começo da legenda é pulada.
+func appendCue(words []timedWord, c cue) []timedWord {
+	incoming := strings.Fields(c.text)
+
+	overlap := 0
+	for k := min(len(incoming), len(words), maxCueOverlap); k > 0; k-- {
+		if sameWords(words[len(words)-k:], incoming[:k]) {
+			overlap = k
+			break
+		}
+	}
+	for _, w := range incoming[overlap:] {
+		words = append(words, timedWord{text: w, at: c.start})
+	}
+	return words
+}
+
+func sameWords(tail []timedWord, head []string) bool {
+	for i := range head {
+		if !strings.EqualFold(tail[i].text, head[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// transcriptSections junta as palavras em frases e as frases em seções rotuladas com o tempo
+// da primeira palavra.
+func transcriptSections(words []timedWord) []Section {
+	var sections []Section
+	var text strings.Builder
+	var start time.Duration
+
+	flush := func() {
+		if text.Len() > 0 {
+			sections = append(sections, Section{Label: formatTimestamp(start), Text: text.String()})
+		}
+		text.Reset()
+	}
+
+	for _, w := range words {
+		if text.Len() == 0 {
+			start = w.at
+		} else {
+			text.WriteByte(' ')
+		}
+		text.WriteString(w.text)
+
+		span := w.at - start
+		last, _ := utf8.DecodeLastRuneInString(w.text)
+		sentenceEnd := strings.ContainsRune(".!?…", last)
+		if sentenceEnd && (span >= transcriptSectionSpan || text.Len() >= transcriptSectionChars) {
+			flush()
+		} else if span >= 2*transcriptSectionSpan || text.Len() >= 2*transcriptSectionChars {
+			flush()
+		}
+	}
+	flush()
+	return sections
+}
+
+// formatTimestamp escreve o tempo como HH:MM:SS, o formato das âncoras de transcrição.
+func formatTimestamp(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+var timestampAnchor = regexp.MustCompile(`(?m)^\[(\d{2}:\d{2}:\d{2})\]$`)
+
+// TimestampAnchors devolve, em ordem, as âncoras de tempo ("00:12:34") das seções de
+// transcrição presentes no texto (Document.Text). Vazio para textos que não são transcrições.
+func TimestampAnchors(text string) []string {
+	var anchors []string
+	for _, m := range timestampAnchor.FindAllStringSubmatch(text, -1) {
+		anchors = append(anchors, m[1])
+	}
+	return anchors
+}
@@ -0,0 +1,111 @@
+package ingestion
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractSubtitlesSRT(t *testing.T) {
+	srt := "1\r\n00:00:01,000 --> 00:00:04,000\r\n<i>A insuficiência cardíaca</i> é uma\r\nsíndrome clínica.\r\n\r\n" +
+		"2\r\n00:00:04,500 --> 00:00:08,000\r\n[Música] Ela ocorre quando o coração\r\n\r\n" +
+		"3\r\n00:01:05,000 --> 00:01:09,000\r\nnão bombeia sangue suficiente. Próximo tema: &amp; fim.\r\n"
+
+	doc, err := ExtractSubtitles([]byte(srt))
+	if err != nil {
+		t.Fatalf("ExtractSubtitles: %v", err)
+	}
+	want := []Section{
+		{Label: "00:00:01", Text: "A insuficiência cardíaca é uma síndrome clínica. Ela ocorre quando o coração não bombeia sangue suficiente."},
+		{Label: "00:01:05", Text: "Próximo tema: & fim."},
+	}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractSubtitlesVTTRollingCaptions(t *testing.T) {
+	vtt := `WEBVTT
+Kind: captions
+
+NOTE gerado automaticamente
+
+STYLE
+::cue { color: red }
+
+00:00.000 --> 00:02.000 align:start position:0%
+a pressão arterial<00:00:01.000><c> é</c><c> regulada</c>
+
+00:02.000 --> 00:04.000 align:start position:0%
+a pressão arterial é regulada
+pelo sistema renina
+
+intro
+00:04.000 --> 00:06.000
+<v Prof>pelo sistema renina angiotensina aldosterona.</v>
+`
+	doc, err := ExtractSubtitles([]byte(vtt))
+	if err != nil {
+		t.Fatalf("ExtractSubtitles: %v", err)
+	}
+	want := []Section{{Label: "00:00:00", Text: "a pressão arterial é regulada pelo sistema renina angiotensina aldosterona."}}
+	if !reflect.DeepEqual(doc.Sections, want) {
+		t.Errorf("sections = %#v, want %#v", doc.Sections, want)
+	}
+}
+
+func TestExtractSubtitlesSplitsOnMultibyteSentenceEnd(t *testing.T) {
+	// A frase que passa de um minuto termina em "…"; a seção precisa fechar nela
+	srt := "1\n00:00:00,000 --> 00:00:30,000\nPrimeira parte da aula\n\n" +
+		"2\n00:01:00,000 --> 00:01:10,000\ncontinua até aqui…\n\n" +
+		"3\n00:01:10,000 --> 00:01:20,000\nNovo assunto.\n"
+
+	doc, err := ExtractSubtitles([]byte(srt))
+	if err != nil {
+		t.Fatalf("ExtractSubtitles: %v", err)
+	}
+	if len(doc.Sections) != 2 || doc.Sections[1].Label != "00:01:10" {
+		t.Fatalf("sections = %#v, want a new section at 00:01:10", doc.Sections)
+	}
+}
+
+func TestExtractSubtitlesWithoutCues(t *testing.T) {
+	if _, err := ExtractSubtitles([]byte("WEBVTT\n\nNOTE nada aqui\n")); !IsRejected(err) {
+		t.Fatalf("error = %v, want a rejection", err)
+	}
+}
+
+func TestTimestampAnchors(t *testing.T) {
+	var b strings.Builder
+	for i, label := range []string{"00:00:01", "00:01:05", "01:02:03"} {
+		fmt.Fprintf(&b, "[%s]\ntrecho %d\n\n", label, i)
+	}
+	b.WriteString("[Página 3]\ntexto\n")
+
+	got := TimestampAnchors(b.String())
+	want := []string{"00:00:01", "00:01:05", "01:02:03"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TimestampAnchors = %v, want %v", got, want)
+	}
+}
+
+func TestDetectSubtitles(t *testing.T) {
+	tests := []struct {
+		name, file, head, want string
+	}{
+		{"vtt", "aula.vtt", "WEBVTT\n\n00:00.000 --> 00:01.000\noi\n", "vtt"},
+		{"vtt with bom", "aula.txt", "\ufeffWEBVTT\n", "vtt"},
+		{"srt by content", "aula.txt", "1\r\n00:00:01,000 --> 00:00:02,000\r\noi\r\n", "srt"},
+		{"srt by extension", "aula.srt", "oi\n", "srt"},
+		{"plain text", "aula.txt", "1\nsó um número\n", "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, ok := DetectContentType(tt.file, []byte(tt.head))
+			if !ok || got != tt.want {
+				t.Errorf("DetectContentType = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
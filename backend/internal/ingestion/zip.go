@@ -0,0 +1,158 @@
+package ingestion
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// DOCX, PPTX e EPUB são arquivos zip com XML dentro. Os limites abaixo protegem contra zip bombs.
+const (
+	maxZipEntry = 32 << 20
+	maxZipTotal = 128 << 20
+)
+
+// zipDocument lê entradas de um zip, somando o que já foi descomprimido.
+type zipDocument struct {
+	files map[string]*zip.File
+	total int64
+}
+
+func openZip(data []byte) (*zipDocument, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a zip archive", ErrInvalidDocument)
+	}
+	z := &zipDocument{files: map[string]*zip.File{}}
+	for _, f := range r.File {
+		z.files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return z, nil
+}
+
+func (z *zipDocument) has(name string) bool {
+	_, ok := z.files[name]
+	return ok
+}
+
+// read devolve o conteúdo da entrada; ok é false se ela não existe.
+func (z *zipDocument) read(name string) ([]byte, bool, error) {
+	f, ok := z.files[name]
+	if !ok {
+		return nil, false, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxZipEntry+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, name, err)
+	}
+	z.total += int64(len(data))
+	if len(data) > maxZipEntry || z.total > maxZipTotal {
+		return nil, true, fmt.Errorf("%w: archive is too large once decompressed", ErrInvalidDocument)
+	}
+	return data, true, nil
+}
+
+// relationship é uma entrada de um arquivo .rels do Office Open XML.
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+// relationships lê o .rels de part ("ppt/slides/slide1.xml" → "ppt/slides/_rels/slide1.xml.rels")
+// e devolve os alvos por ID, já como caminhos dentro do zip.
+func (z *zipDocument) relationships(part string) (map[string]relationship, error) {
+	dir, file := path.Split(part)
+	data, ok, err := z.read(dir + "_rels/" + file + ".rels")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var rels struct {
+		Items []relationship `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("%w: %s.rels: %v", ErrInvalidDocument, part, err)
+	}
+
+	byID := map[string]relationship{}
+	for _, r := range rels.Items {
+		r.Target = resolvePath(dir, r.Target)
+		byID[r.ID] = r
+	}
+	return byID, nil
+}
+
+// resolvePath resolve target relativo à pasta dir ("/" no começo é a raiz do zip).
+func resolvePath(dir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Clean("/"+dir+target), "/")
+}
+
+// ooxmlParagraphs lê o texto dos parágrafos de um XML do Office. paragraph é o nome local do
+// elemento de parágrafo ("p" em DOCX e PPTX) e text, o do texto ("t"). Tabulações e quebras de
+// linha explícitas viram espaço e quebra de linha. onParagraph recebe cada parágrafo e o
+// estilo dele (w:pStyle), quando houver.
+func ooxmlParagraphs(data []byte, onParagraph func(text, style string)) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var b strings.Builder
+	var style string
+	inText := false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				b.Reset()
+				style = ""
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte(' ')
+			case "br", "cr":
+				b.WriteByte('\n')
+			case "pStyle":
+				for _, a := range t.Attr {
+					if a.Name.Local == "val" {
+						style = a.Value
+					}
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if text := strings.TrimSpace(b.String()); text != "" {
+					onParagraph(text, style)
+				}
+				b.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+}
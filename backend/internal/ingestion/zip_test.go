@@ -0,0 +1,113 @@
+package ingestion
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// zipEntry é um arquivo do zip montado pelos testes, na ordem em que é gravado.
+type zipEntry struct {
+	name, body string
+}
+
+// buildZip monta em memória um zip com as entradas dadas (DOCX, PPTX e EPUB de teste). O
+// "mimetype" do EPUB vai sem compressão, como a especificação pede.
+func buildZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.name == "mimetype" {
+			header.Method = zip.Store
+		}
+		f, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestZipDocumentLimits(t *testing.T) {
+	zeros := strings.Repeat("\x00", maxZipTotal/4-1)
+	data := buildZip(t,
+		zipEntry{"big.xml", zeros},
+		zipEntry{"huge.xml", strings.Repeat("\x00", maxZipEntry+1)},
+	)
+
+	z, err := openZip(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := z.read("huge.xml"); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("entry over maxZipEntry: err = %v, want ErrInvalidDocument", err)
+	}
+
+	// Cada leitura soma no total, mesmo repetindo a entrada
+	z, _ = openZip(data)
+	for i := 0; i < 4; i++ {
+		if _, _, err := z.read("big.xml"); err != nil {
+			t.Fatalf("read %d under maxZipTotal: %v", i+1, err)
+		}
+	}
+	if _, _, err := z.read("big.xml"); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("read past maxZipTotal: err = %v, want ErrInvalidDocument", err)
+	}
+}
+
+func TestZipDocumentRejectsNonZip(t *testing.T) {
+	for _, extract := range []func([]byte) (Document, error){ExtractDOCX, ExtractPPTX, ExtractEPUB} {
+		if _, err := extract([]byte("não é um zip")); !errors.Is(err, ErrInvalidDocument) {
+			t.Errorf("err = %v, want ErrInvalidDocument", err)
+		}
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct{ dir, target, want string }{
+		{"ppt/slides/", "../notesSlides/notesSlide1.xml", "ppt/notesSlides/notesSlide1.xml"},
+		{"ppt/", "slides/slide1.xml", "ppt/slides/slide1.xml"},
+		{"ppt/slides/", "/ppt/media/image1.png", "ppt/media/image1.png"},
+		{"OEBPS/", "../../../etc/passwd", "etc/passwd"},
+	}
+	for _, tt := range tests {
+		if got := resolvePath(tt.dir, tt.target); got != tt.want {
+			t.Errorf("resolvePath(%q, %q) = %q, want %q", tt.dir, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestDetectZipFormats(t *testing.T) {
+	docx := buildZip(t, zipEntry{"word/document.xml", "<w:document/>"})
+	epub := buildZip(t, zipEntry{"mimetype", "application/epub+zip"}, zipEntry{"META-INF/container.xml", "<container/>"})
+
+	tests := []struct {
+		name, file string
+		data       []byte
+		want       string
+		ok         bool
+	}{
+		{"docx", "aula.docx", docx, "docx", true},
+		{"pptx", "Aula.PPTX", docx, "pptx", true},
+		{"epub by content", "livro.zip", epub, "epub", true},
+		{"unknown zip", "arquivos.zip", docx, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, ok := DetectContentType(tt.file, tt.data[:min(len(tt.data), SniffLen)])
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("DetectContentType = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
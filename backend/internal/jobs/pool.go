@@ -0,0 +1,203 @@
+// Package jobs processa em segundo plano os jobs de geração enfileirados em generation_jobs.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+)
+
+// Config ajusta o pool de workers.
+type Config struct {
+	// Workers é quantos jobs rodam ao mesmo tempo nesta instância
+	Workers int
+	// PollInterval é a espera entre consultas quando a fila está vazia
+	PollInterval time.Duration
+	// HeartbeatInterval é a frequência com que o worker grava progresso e confere cancelamento
+	HeartbeatInterval time.Duration
+	// StaleAfter é quanto tempo sem heartbeat faz um job "running" voltar a ser pego
+	StaleAfter time.Duration
+	// MaxAttempts limita quantas vezes um job abandonado é retomado antes de falhar
+	MaxAttempts int
+}
+
+// DefaultConfig são os valores usados pelo servidor.
+var DefaultConfig = Config{
+	Workers:           2,
+	PollInterval:      time.Second,
+	HeartbeatInterval: 5 * time.Second,
+	StaleAfter:        2 * time.Minute,
+	MaxAttempts:       3,
+}
+
+// Faixas de progresso: ao começar o job já mostra algo; a geração vai até 90 e o 100 vem ao salvar.
+const (
+	progressStarted   = 5
+	progressGenerated = 90
+)
+
+// Pool consome a fila com Config.Workers goroutines.
+type Pool struct {
+	cfg              Config
+	repo             repository.JobRepository
+	generator        llm.Generator
+	flashcardService services.FlashcardService
+	tx               repository.Transactor
+	wg               sync.WaitGroup
+}
+
+func NewPool(cfg Config, repo repository.JobRepository, generator llm.Generator, flashcardService services.FlashcardService, tx repository.Transactor) *Pool {
+	return &Pool{cfg: cfg, repo: repo, generator: generator, flashcardService: flashcardService, tx: tx}
+}
+
+// Start sobe os workers. Eles param quando ctx é cancelado; jobs interrompidos voltam para a fila.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.loop(ctx)
+		}()
+	}
+	log.Printf("Pool de jobs iniciado com %d workers", p.cfg.Workers)
+}
+
+// Wait bloqueia até todos os workers terminarem, depois que o contexto de Start foi cancelado.
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pool) loop(ctx context.Context) {
+	for {
+		job, err := p.repo.ClaimNext(ctx, p.cfg.StaleAfter)
+		switch {
+		case err == nil:
+			p.process(ctx, job)
+			continue
+		case ctx.Err() != nil:
+			return
+		case !errors.Is(err, sql.ErrNoRows):
+			log.Printf("Erro ao buscar job na fila: %v", err)
+		}
+
+		// Fila vazia (ou erro): espera um pouco, com jitter para os workers não consultarem juntos
+		wait := p.cfg.PollInterval + time.Duration(rand.Int64N(int64(p.cfg.PollInterval)/2+1))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (p *Pool) process(ctx context.Context, job model.GenerationJob) {
+	if job.Attempts > p.cfg.MaxAttempts {
+		p.fail(job, errors.New("job abandoned too many times"))
+		return
+	}
+
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+
+	var progress atomic.Int64
+	progress.Store(progressStarted)
+	var cancelled atomic.Bool
+
+	// Heartbeat: mantém o job vivo, grava o progresso e percebe cancelamentos feitos pela API
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(p.cfg.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			status, err := p.repo.Heartbeat(ctx, job.ID, int(progress.Load()))
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Erro no heartbeat do job %s: %v", job.ID, err)
+			}
+			if status == model.JobCancelled {
+				cancelled.Store(true)
+				cancelJob()
+				return
+			}
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	genCtx := llm.WithProgress(jobCtx, func(done, total int) {
+		progress.Store(int64(progressStarted + (progressGenerated-progressStarted)*done/total))
+	})
+
+	err := p.run(genCtx, job)
+	cancelJob()
+	<-heartbeatDone
+
+	switch {
+	case cancelled.Load():
+		log.Printf("Job %s cancelado pelo usuário", job.ID)
+	case ctx.Err() != nil:
+		// Servidor desligando: outro worker (ou esta instância, ao voltar) retoma o job
+		requeueCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := p.repo.Requeue(requeueCtx, job.ID); err != nil {
+			log.Printf("Erro ao devolver o job %s para a fila: %v", job.ID, err)
+		}
+	case err != nil:
+		p.fail(job, err)
+	default:
+		log.Printf("Job %s concluído", job.ID)
+	}
+}
+
+// run gera os flashcards e, numa única transação, cria o set e marca o job como concluído.
+// Se o job foi cancelado nesse meio-tempo, Complete não encontra o job rodando e nada é gravado.
+func (p *Pool) run(ctx context.Context, job model.GenerationJob) error {
+	if job.Kind != model.JobKindPrompt {
+		return fmt.Errorf("unsupported job kind %q", job.Kind)
+	}
+
+	var req model.PromptRequest
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	generated, err := p.generator.GenerateFlashcards(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		difficulty := req.Level.OrDefault()
+		set := model.FlashcardSet{UserID: job.UserID, Topic: req.Prompt, Difficulty: &difficulty}
+		set, _, err := p.flashcardService.CreateSetWithFlashcards(ctx, set, generated.Flashcards)
+		if err != nil {
+			return err
+		}
+		return p.repo.Complete(ctx, job.ID, set.ID)
+	})
+}
+
+func (p *Pool) fail(job model.GenerationJob, cause error) {
+	log.Printf("Job %s falhou: %v", job.ID, cause)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.repo.Fail(ctx, job.ID, cause.Error()); err != nil {
+		log.Printf("Erro ao marcar o job %s como falho: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,241 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/services"
+	"github.com/google/uuid"
+)
+
+// fakeJobRepo guarda o que o pool fez com o job. Heartbeat devolve status, que o teste muda
+// para simular um cancelamento feito pela API.
+type fakeJobRepo struct {
+	repository.JobRepository
+
+	mu          sync.Mutex
+	status      string
+	heartbeats  int
+	completeErr error
+	completed   *uuid.UUID
+	failed      string
+	requeued    bool
+}
+
+func (r *fakeJobRepo) Heartbeat(ctx context.Context, id uuid.UUID, progress int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.heartbeats++
+	return r.status, nil
+}
+
+func (r *fakeJobRepo) Complete(ctx context.Context, id uuid.UUID, setID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.completeErr != nil {
+		return r.completeErr
+	}
+	r.completed = &setID
+	return nil
+}
+
+func (r *fakeJobRepo) Fail(ctx context.Context, id uuid.UUID, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = message
+	return nil
+}
+
+func (r *fakeJobRepo) Requeue(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requeued = true
+	return nil
+}
+
+func (r *fakeJobRepo) setStatus(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// fakeGenerator avisa em started quando a geração começa. Com block, só termina quando o
+// contexto é cancelado, como uma chamada ao LLM interrompida.
+type fakeGenerator struct {
+	started chan struct{}
+	block   bool
+	calls   int
+}
+
+func (g *fakeGenerator) GenerateFlashcards(ctx context.Context, req model.PromptRequest) (model.FlashcardsResponse, error) {
+	g.calls++
+	close(g.started)
+	if g.block {
+		<-ctx.Done()
+		return model.FlashcardsResponse{}, ctx.Err()
+	}
+	return model.FlashcardsResponse{Flashcards: []model.Flashcard{{QuestionText: "Q", AnswerText: "A"}}}, nil
+}
+
+func (g *fakeGenerator) GenerateFlashcardsFromSummary(ctx context.Context, req model.SummaryRequest) (model.FlashcardsResponse, error) {
+	return model.FlashcardsResponse{}, errors.New("not used")
+}
+
+type fakeFlashcardService struct {
+	services.FlashcardService
+	setID uuid.UUID
+}
+
+func (s *fakeFlashcardService) CreateSetWithFlashcards(ctx context.Context, set model.FlashcardSet, cards []model.Flashcard) (model.FlashcardSet, []model.Flashcard, error) {
+	set.ID = s.setID
+	return set, cards, nil
+}
+
+// fakeTransactor conta commits e rollbacks conforme o retorno de fn.
+type fakeTransactor struct {
+	commits   int
+	rollbacks int
+}
+
+func (t *fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		t.rollbacks++
+		return err
+	}
+	t.commits++
+	return nil
+}
+
+type poolFixture struct {
+	pool *Pool
+	repo *fakeJobRepo
+	gen  *fakeGenerator
+	tx   *fakeTransactor
+	set  uuid.UUID
+	job  model.GenerationJob
+}
+
+func newPoolFixture(t *testing.T, block bool) *poolFixture {
+	t.Helper()
+	payload, err := json.Marshal(model.PromptRequest{Prompt: "Fotossíntese"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &poolFixture{
+		repo: &fakeJobRepo{status: model.JobRunning},
+		gen:  &fakeGenerator{started: make(chan struct{}), block: block},
+		tx:   &fakeTransactor{},
+		set:  uuid.New(),
+		job:  model.GenerationJob{ID: uuid.New(), UserID: uuid.New(), Kind: model.JobKindPrompt, Payload: payload, Attempts: 1},
+	}
+	cfg := Config{Workers: 1, PollInterval: time.Millisecond, HeartbeatInterval: time.Millisecond, StaleAfter: time.Minute, MaxAttempts: 3}
+	f.pool = NewPool(cfg, f.repo, f.gen, &fakeFlashcardService{setID: f.set}, f.tx)
+	return f
+}
+
+// processAsync roda process numa goroutine e devolve um canal fechado quando ele termina.
+func (f *poolFixture) processAsync(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.pool.process(ctx, f.job)
+	}()
+	return done
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestProcessCompletesJob(t *testing.T) {
+	f := newPoolFixture(t, false)
+	f.pool.process(context.Background(), f.job)
+
+	if f.repo.completed == nil || *f.repo.completed != f.set {
+		t.Fatalf("completed = %v, want set %s", f.repo.completed, f.set)
+	}
+	if f.tx.commits != 1 || f.repo.failed != "" || f.repo.requeued {
+		t.Errorf("commits = %d, failed = %q, requeued = %v", f.tx.commits, f.repo.failed, f.repo.requeued)
+	}
+}
+
+func TestProcessCancelledMidRun(t *testing.T) {
+	f := newPoolFixture(t, true)
+	done := f.processAsync(context.Background())
+
+	waitFor(t, f.gen.started, "generation to start")
+	f.repo.setStatus(model.JobCancelled)
+	waitFor(t, done, "the cancelled job to stop")
+
+	if f.repo.completed != nil || f.repo.failed != "" || f.repo.requeued {
+		t.Errorf("cancelled job was touched: completed = %v, failed = %q, requeued = %v",
+			f.repo.completed, f.repo.failed, f.repo.requeued)
+	}
+	if f.tx.commits+f.tx.rollbacks != 0 {
+		t.Error("cancelled job should not open a transaction")
+	}
+}
+
+func TestProcessRequeuesOnShutdown(t *testing.T) {
+	f := newPoolFixture(t, true)
+	ctx, shutdown := context.WithCancel(context.Background())
+	done := f.processAsync(ctx)
+
+	waitFor(t, f.gen.started, "generation to start")
+	shutdown()
+	waitFor(t, done, "the interrupted job to stop")
+
+	if !f.repo.requeued {
+		t.Error("interrupted job was not requeued")
+	}
+	if f.repo.failed != "" || f.repo.completed != nil {
+		t.Errorf("interrupted job should only be requeued: failed = %q, completed = %v", f.repo.failed, f.repo.completed)
+	}
+}
+
+func TestProcessFailsJobAbandonedTooManyTimes(t *testing.T) {
+	f := newPoolFixture(t, false)
+	f.job.Attempts = f.pool.cfg.MaxAttempts + 1
+	f.pool.process(context.Background(), f.job)
+
+	if f.repo.failed == "" {
+		t.Error("job past MaxAttempts was not failed")
+	}
+	if f.gen.calls != 0 || f.repo.heartbeats != 0 {
+		t.Errorf("job past MaxAttempts should not run: generator calls = %d, heartbeats = %d", f.gen.calls, f.repo.heartbeats)
+	}
+
+	// No limite ainda roda
+	f = newPoolFixture(t, false)
+	f.job.Attempts = f.pool.cfg.MaxAttempts
+	f.pool.process(context.Background(), f.job)
+	if f.repo.completed == nil {
+		t.Error("job at MaxAttempts should still run")
+	}
+}
+
+func TestProcessRollsBackWhenJobNoLongerRunning(t *testing.T) {
+	f := newPoolFixture(t, false)
+	f.repo.completeErr = sql.ErrNoRows
+	f.pool.process(context.Background(), f.job)
+
+	if f.tx.rollbacks != 1 || f.tx.commits != 0 {
+		t.Errorf("commits = %d, rollbacks = %d; want the set creation rolled back", f.tx.commits, f.tx.rollbacks)
+	}
+	if f.repo.completed != nil {
+		t.Error("job should not be marked completed")
+	}
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	if !b.allow() {
+		t.Fatal("breaker opened before reaching the threshold")
+	}
+	b.failure()
+	if b.allow() {
+		t.Fatal("breaker should refuse calls after the threshold")
+	}
+	if !b.isOpen() {
+		t.Fatal("isOpen should report the open breaker")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("breaker should let a probe through after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("only one probe should pass while half-open")
+	}
+	b.success()
+	if !b.allow() {
+		t.Fatal("a successful probe should close the breaker")
+	}
+}
+
+func TestBreakerFailedProbeReopens(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("breaker should let a probe through after the cooldown")
+	}
+	b.failure()
+	if b.allow() {
+		t.Fatal("a failed probe should reopen the breaker for a new cooldown")
+	}
+}
+
+func TestBreakerCancelledProbe(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("breaker should let a probe through after the cooldown")
+	}
+
+	// O chamador desistiu no meio do teste: o breaker não pode ficar preso em half-open
+	b.cancelled()
+	if !b.allow() {
+		t.Fatal("after a cancelled probe the next call should probe again")
+	}
+	b.success()
+	if !b.allow() || b.isOpen() {
+		t.Fatal("a successful probe should close the breaker")
+	}
+}
+
+func TestBreakerCancelledWhileClosed(t *testing.T) {
+	b := newBreaker(1, time.Minute)
+	b.cancelled()
+	if !b.allow() {
+		t.Fatal("cancellation should not open a closed breaker")
+	}
+}
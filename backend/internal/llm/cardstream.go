@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// cardStreamParser lê o array JSON de flashcards aos pedaços, como chega do stream do modelo,
+// e devolve cada objeto assim que a chave que o fecha aparece. O bloco <think> e o que vier
+// antes do '[' (como o cercado ```json) são ignorados. A resposta completa continua sendo
+// validada por utils.ParseFlashcardsResponse no fim; isto só antecipa os cards já prontos.
+type cardStreamParser struct {
+	buf      strings.Builder
+	pos      int  // próximo byte de buf a examinar
+	inArray  bool // já passou do '[' de abertura
+	done     bool // achou o ']' de fechamento
+	depth    int  // profundidade de chaves/colchetes dentro do array
+	objStart int  // início do objeto atual em buf
+	inString bool
+	escaped  bool
+}
+
+const (
+	thinkOpen  = "<think>"
+	thinkClose = "</think>"
+)
+
+// Write acrescenta um pedaço da resposta e devolve os cards que ficaram completos com ele.
+// Objetos que não são flashcards válidos são ignorados.
+func (p *cardStreamParser) Write(delta string) []model.FlashcardRaw {
+	p.buf.WriteString(delta)
+	text := p.buf.String()
+
+	var cards []model.FlashcardRaw
+	for !p.done && p.pos < len(text) {
+		if !p.inArray {
+			if !p.seekArray(text) {
+				break
+			}
+			continue
+		}
+
+		ch := text[p.pos]
+		p.pos++
+
+		if p.inString {
+			switch {
+			case p.escaped:
+				p.escaped = false
+			case ch == '\\':
+				p.escaped = true
+			case ch == '"':
+				p.inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			p.inString = true
+		case '{', '[':
+			if p.depth == 0 && ch == '{' {
+				p.objStart = p.pos - 1
+			}
+			p.depth++
+		case '}', ']':
+			if p.depth == 0 {
+				// ']' do próprio array: acabou
+				p.done = true
+				break
+			}
+			p.depth--
+			if p.depth == 0 && ch == '}' {
+				var card model.FlashcardRaw
+				if err := json.Unmarshal([]byte(text[p.objStart:p.pos]), &card); err == nil && card.Front != "" && card.Back != "" {
+					cards = append(cards, card)
+				}
+			}
+		}
+	}
+	return cards
+}
+
+// seekArray avança até depois do '[' de abertura, pulando o bloco <think>. Devolve false
+// quando precisa de mais texto para decidir.
+func (p *cardStreamParser) seekArray(text string) bool {
+	rest := text[p.pos:]
+	bracket := strings.IndexByte(rest, '[')
+	think := strings.Index(rest, thinkOpen)
+
+	if think >= 0 && (bracket < 0 || think < bracket) {
+		end := strings.Index(rest[think:], thinkClose)
+		if end < 0 {
+			return false
+		}
+		p.pos += think + end + len(thinkClose)
+		return true
+	}
+	if bracket < 0 {
+		p.pos = len(text)
+		// Guarda o fim do texto caso seja o começo de um "<think>" partido entre pedaços
+		if i := strings.LastIndexByte(rest, '<'); i >= 0 {
+			p.pos = len(text) - len(rest) + i
+		}
+		return false
+	}
+
+	p.pos += bracket + 1
+	p.inArray = true
+	return true
+}
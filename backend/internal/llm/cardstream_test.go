@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// feed entrega o texto ao parser em pedaços de n bytes, como um stream faria.
+func feed(p *cardStreamParser, text string, n int) []model.FlashcardRaw {
+	var cards []model.FlashcardRaw
+	for i := 0; i < len(text); i += n {
+		end := min(i+n, len(text))
+		cards = append(cards, p.Write(text[i:end])...)
+	}
+	return cards
+}
+
+func TestCardStreamParser(t *testing.T) {
+	response := "<think>pensando em [colchetes] e {chaves}</think>\n```json\n[\n" +
+		`{"front": "O que é {x}?", "back": "Um \"placeholder\" com ] dentro", "tags": ["a", "b"]},` + "\n" +
+		`{"front": "sem verso"},` + "\n" +
+		`{"front": "Capital da França?", "back": "Paris"}` + "\n]\n```\n" +
+		`[{"front": "depois do fim", "back": "ignorado"}]`
+
+	for _, n := range []int{1, 3, 7, len(response)} {
+		cards := feed(&cardStreamParser{}, response, n)
+		if len(cards) != 2 {
+			t.Fatalf("chunk %d: got %d cards, want 2: %+v", n, len(cards), cards)
+		}
+		if cards[0].Back != `Um "placeholder" com ] dentro` || cards[1].Front != "Capital da França?" {
+			t.Errorf("chunk %d: unexpected cards %+v", n, cards)
+		}
+	}
+}
+
+func TestCardStreamParserWaitsForCompleteObject(t *testing.T) {
+	var p cardStreamParser
+	if cards := p.Write(`[{"front": "Q", "back": "A`); len(cards) != 0 {
+		t.Fatalf("emitted %d cards before the object closed", len(cards))
+	}
+	if cards := p.Write(`"}`); len(cards) != 1 {
+		t.Fatalf("got %d cards after the object closed, want 1", len(cards))
+	}
+}
+
+func TestCardStreamParserSplitThinkTag(t *testing.T) {
+	var p cardStreamParser
+	p.Write("<thi")
+	p.Write("nk>[nada]</th")
+	cards := p.Write(`ink>[{"front": "Q", "back": "A"}]`)
+	if len(cards) != 1 || cards[0].Front != "Q" {
+		t.Fatalf("got %+v, want the card after the think block", cards)
+	}
+}
@@ -0,0 +1,359 @@
+// internal/llm/client.go
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChatRequest represents the request body of an OpenAI-compatible /chat/completions call
+type ChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream,omitempty"`
+}
+
+// ChatResponse represents the response body of an OpenAI-compatible /chat/completions call
+type ChatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+// ChatStreamChunk is one "data:" event of a streamed (stream: true) /chat/completions call
+type ChatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// Chatter envia uma conversa para o modelo e devolve o texto da resposta.
+// É o ponto de troca para testes e para outros tipos de provider.
+type Chatter interface {
+	Chat(ctx context.Context, messages []Message) (string, error)
+}
+
+// StreamChatter é um Chatter que também entrega a resposta aos pedaços, conforme o modelo gera.
+// onDelta é chamado na goroutine de quem chamou; o retorno é o texto completo, como em Chat.
+type StreamChatter interface {
+	Chatter
+	ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error)
+}
+
+// APIError é uma resposta não-200 do provider.
+type APIError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter vem do header Retry-After (zero se ausente ou inválido)
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("LLM API error (%d): %s", e.StatusCode, e.Body)
+}
+
+// retryable diz se vale tentar de novo: limite de taxa ou erro do lado do provider.
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Client fala com qualquer endpoint compatível com a API de chat da OpenAI
+// (DeepInfra, OpenAI, Ollama, servidor do llama.cpp, ...). Cada tentativa tem timeout próprio;
+// 429, 5xx e falhas de rede são repetidos com backoff exponencial e jitter, e falhas seguidas
+// abrem o circuit breaker do provider.
+type Client struct {
+	baseURL    string
+	model      string
+	apiKey     string
+	httpClient *http.Client
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	breaker    *breaker
+}
+
+// NewClient cria um Client a partir da configuração. O http.Client é reaproveitado entre chamadas.
+// Timeout, backoff e breaker zerados assumem os valores padrão; MaxRetries zero desliga as repetições
+// e BreakerThreshold negativo desliga o breaker.
+func NewClient(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		model:      cfg.Model,
+		apiKey:     cfg.APIKey,
+		httpClient: httpClient,
+		timeout:    cfg.Timeout,
+		maxRetries: cfg.MaxRetries,
+		backoff:    cfg.Backoff,
+		maxBackoff: cfg.MaxBackoff,
+		breaker:    newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// Model devolve o modelo configurado, útil para logs.
+func (c *Client) Model() string {
+	return c.model
+}
+
+// CircuitOpen reporta se o breaker deste provider está recusando chamadas.
+func (c *Client) CircuitOpen() bool {
+	return c.breaker.isOpen()
+}
+
+// Chat faz a chamada a /chat/completions e devolve o conteúdo da primeira escolha,
+// repetindo as falhas transitórias até maxRetries vezes.
+func (c *Client) Chat(ctx context.Context, messages []Message) (string, error) {
+	reqBody, err := json.Marshal(ChatRequest{Model: c.model, Messages: messages})
+	if err != nil {
+		return "", err
+	}
+
+	return c.withRetries(ctx, func(ctx context.Context) (string, error) {
+		return c.do(ctx, reqBody)
+	})
+}
+
+// ChatStream faz a chamada com stream: true e repassa cada pedaço do conteúdo para onDelta.
+// Falhas antes do primeiro pedaço são repetidas como em Chat; depois dele a resposta já foi
+// parcialmente entregue e o erro volta direto (errStreamInterrupted).
+func (c *Client) ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error) {
+	reqBody, err := json.Marshal(ChatRequest{Model: c.model, Messages: messages, Stream: true})
+	if err != nil {
+		return "", err
+	}
+
+	return c.withRetries(ctx, func(ctx context.Context) (string, error) {
+		return c.doStream(ctx, reqBody, onDelta)
+	})
+}
+
+// withRetries roda attempt até dar certo, repetindo as falhas transitórias com backoff
+// e registrando o resultado de cada tentativa no breaker.
+func (c *Client) withRetries(ctx context.Context, attempt func(ctx context.Context) (string, error)) (string, error) {
+	for n := 0; ; n++ {
+		if !c.breaker.allow() {
+			return "", ErrCircuitOpen
+		}
+
+		content, err := attempt(ctx)
+		if err == nil {
+			c.breaker.success()
+			return content, nil
+		}
+
+		// Cancelamento de quem chamou não é culpa do provider
+		if ctx.Err() != nil {
+			c.breaker.cancelled()
+			return "", ctx.Err()
+		}
+
+		if errors.Is(err, errStreamInterrupted) {
+			// Parte da resposta já foi entregue: conta como falha, mas não dá para repetir
+			c.breaker.failure()
+			return "", err
+		}
+
+		var apiErr *APIError
+		transient := !errors.Is(err, errBadResponse) && (!errors.As(err, &apiErr) || apiErr.retryable())
+		if !transient {
+			// 4xx ou resposta fora do formato: o provider respondeu, repetir não muda nada
+			c.breaker.success()
+			return "", err
+		}
+		c.breaker.failure()
+
+		if n >= c.maxRetries {
+			return "", err
+		}
+
+		wait := c.backoffFor(n)
+		if apiErr != nil && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+		if wait > maxRetryAfter {
+			return "", err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return "", err
+		}
+
+		log.Printf("LLM %s falhou (tentativa %d/%d): %v; nova tentativa em %s", c.model, n+1, c.maxRetries+1, err, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// maxRetryAfter é o maior Retry-After que aceitamos esperar dentro de uma requisição do usuário.
+const maxRetryAfter = 30 * time.Second
+
+// backoffFor devolve a espera antes da próxima tentativa: exponencial com "full jitter"
+// (valor aleatório entre zero e backoff*2^attempt, limitado a maxBackoff).
+func (c *Client) backoffFor(attempt int) time.Duration {
+	ceiling := c.backoff << attempt
+	if ceiling <= 0 || ceiling > c.maxBackoff {
+		ceiling = c.maxBackoff
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// do faz uma única tentativa, limitada por c.timeout.
+func (c *Client) do(ctx context.Context, reqBody []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.post(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var apiResponse ChatResponse
+	if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil {
+		return "", fmt.Errorf("%w: %v", errBadResponse, err)
+	}
+
+	if len(apiResponse.Choices) == 0 {
+		return "", fmt.Errorf("%w: empty choices", errBadResponse)
+	}
+
+	return apiResponse.Choices[0].Message.Content, nil
+}
+
+// doStream faz uma única tentativa em modo stream, também limitada por c.timeout, lendo os
+// eventos "data:" até o [DONE] (ou o fim do corpo).
+func (c *Client) doStream(ctx context.Context, reqBody []byte, onDelta func(delta string)) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.post(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			// Linhas em branco, comentários (": keep-alive") e outros campos do SSE
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", c.streamFailure(&content, fmt.Errorf("%w: %v", errBadResponse, err))
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		onDelta(delta)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", c.streamFailure(&content, err)
+	}
+
+	if content.Len() == 0 {
+		return "", fmt.Errorf("%w: empty stream", errBadResponse)
+	}
+	return content.String(), nil
+}
+
+// streamFailure marca o erro como interrupção se algum pedaço já foi entregue.
+func (c *Client) streamFailure(content *strings.Builder, err error) error {
+	if content.Len() > 0 {
+		return fmt.Errorf("%w after %d bytes: %v", errStreamInterrupted, content.Len(), err)
+	}
+	return err
+}
+
+// post envia o corpo para /chat/completions e devolve a resposta se o status for 200;
+// qualquer outro status vira APIError.
+func (c *Client) post(ctx context.Context, reqBody []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// Servidores locais (Ollama, llama.cpp) não exigem chave
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(bodyBytes),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	return resp, nil
+}
+
+// errBadResponse marca respostas 200 que não seguem o formato esperado; repetir não ajuda.
+var errBadResponse = errors.New("invalid LLM API response")
+
+// errStreamInterrupted marca streams que falharam depois de entregar parte da resposta.
+var errStreamInterrupted = errors.New("LLM stream interrupted")
+
+// parseRetryAfter aceita os dois formatos do header: segundos ou data HTTP.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
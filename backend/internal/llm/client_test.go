@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Um teste do half-open cancelado pelo chamador não pode deixar o provider desligado para sempre.
+func TestClientCancelledProbeDoesNotWedgeBreaker(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Lê o corpo todo para o servidor perceber quando o cliente desiste da conexão
+		io.Copy(io.Discard, r.Body)
+		switch calls.Add(1) {
+		case 1:
+			http.Error(w, "upstream down", http.StatusBadGateway)
+		case 2:
+			<-r.Context().Done()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	client := NewClient(Config{BaseURL: srv.URL, Model: "test", BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	client.breaker.now = func() time.Time { return now }
+
+	if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err == nil {
+		t.Fatal("expected the 502 to fail the call")
+	}
+	if !client.CircuitOpen() {
+		t.Fatal("breaker should be open after the failure")
+	}
+
+	now = now.Add(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Chat(ctx, []Message{{Role: "user", Content: "hi"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("probe error = %v, want context.DeadlineExceeded", err)
+	}
+
+	content, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("call after a cancelled probe: %v", err)
+	}
+	if content != "ok" {
+		t.Fatalf("content = %q, want %q", content, "ok")
+	}
+	if client.CircuitOpen() {
+		t.Fatal("breaker should be closed after a successful probe")
+	}
+}
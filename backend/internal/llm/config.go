@@ -0,0 +1,186 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config aponta o Client para um endpoint compatível com a API da OpenAI.
+type Config struct {
+	BaseURL    string
+	Model      string
+	APIKey     string
+	HTTPClient *http.Client
+
+	// Timeout limita cada tentativa (não a chamada inteira, que segue o contexto)
+	Timeout time.Duration
+	// MaxRetries é quantas vezes repetir após 429, 5xx ou falha de rede
+	MaxRetries int
+	// Backoff é a espera base antes da primeira repetição; dobra a cada tentativa até MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// BreakerThreshold falhas seguidas abrem o circuit breaker por BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Valores padrão de resiliência. O timeout é generoso porque modelos de raciocínio
+// como o DeepSeek-R1 podem passar minutos "pensando" antes de responder.
+const (
+	DefaultTimeout          = 3 * time.Minute
+	DefaultMaxRetries       = 3
+	DefaultBackoff          = 500 * time.Millisecond
+	DefaultMaxBackoff       = 10 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = DefaultBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	if c.BreakerThreshold == 0 {
+		c.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = DefaultBreakerCooldown
+	}
+	return c
+}
+
+// Preset traz a URL e o modelo padrão de um provider conhecido.
+type Preset struct {
+	BaseURL    string
+	Model      string
+	RequireKey bool
+}
+
+// Presets aceitos em LLM_PROVIDER. LLM_BASE_URL e LLM_MODEL sobrescrevem os valores do preset.
+var Presets = map[string]Preset{
+	"deepinfra": {BaseURL: "https://api.deepinfra.com/v1/openai", Model: "deepseek-ai/DeepSeek-R1", RequireKey: true},
+	"openai":    {BaseURL: "https://api.openai.com/v1", Model: "gpt-4o-mini", RequireKey: true},
+	"ollama":    {BaseURL: "http://localhost:11434/v1", Model: "llama3.1"},
+	"llamacpp":  {BaseURL: "http://localhost:8080/v1", Model: "local"},
+}
+
+// DefaultProvider mantém o comportamento anterior: DeepSeek-R1 na DeepInfra.
+const DefaultProvider = "deepinfra"
+
+// ConfigFromEnv monta a Config a partir de LLM_PROVIDER, LLM_BASE_URL, LLM_MODEL e LLM_API_KEY.
+// DEEPISEEK_API_KEY continua aceita como chave quando LLM_API_KEY não está definida.
+func ConfigFromEnv() (Config, error) {
+	provider := strings.ToLower(os.Getenv("LLM_PROVIDER"))
+	if provider == "" {
+		provider = DefaultProvider
+	}
+
+	preset, ok := Presets[provider]
+	if !ok && os.Getenv("LLM_BASE_URL") == "" {
+		return Config{}, fmt.Errorf("unknown LLM_PROVIDER %q: set LLM_BASE_URL and LLM_MODEL for custom endpoints", provider)
+	}
+
+	cfg := Config{
+		BaseURL: envOr("LLM_BASE_URL", preset.BaseURL),
+		Model:   envOr("LLM_MODEL", preset.Model),
+		APIKey:  envOr("LLM_API_KEY", os.Getenv("DEEPISEEK_API_KEY")),
+	}
+
+	var err error
+	if cfg.Timeout, err = envDuration("LLM_TIMEOUT", DefaultTimeout); err != nil {
+		return Config{}, err
+	}
+	if cfg.MaxRetries, err = envInt("LLM_MAX_RETRIES", DefaultMaxRetries); err != nil {
+		return Config{}, err
+	}
+	if cfg.BreakerThreshold, err = envInt("LLM_BREAKER_THRESHOLD", DefaultBreakerThreshold); err != nil {
+		return Config{}, err
+	}
+	if cfg.BreakerCooldown, err = envDuration("LLM_BREAKER_COOLDOWN", DefaultBreakerCooldown); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Model == "" {
+		return Config{}, fmt.Errorf("LLM_MODEL is required for provider %q", provider)
+	}
+	if preset.RequireKey && cfg.APIKey == "" {
+		return Config{}, fmt.Errorf("LLM_API_KEY is required for provider %q", provider)
+	}
+
+	return cfg, nil
+}
+
+// FallbackConfigFromEnv monta a Config do modelo reserva a partir de LLM_FALLBACK_MODEL.
+// LLM_FALLBACK_BASE_URL e LLM_FALLBACK_API_KEY são opcionais e, se ausentes, repetem o principal.
+// Retorna false quando não há modelo reserva configurado.
+func FallbackConfigFromEnv(primary Config) (Config, bool) {
+	model := os.Getenv("LLM_FALLBACK_MODEL")
+	if model == "" {
+		return Config{}, false
+	}
+
+	cfg := primary
+	cfg.Model = model
+	cfg.BaseURL = envOr("LLM_FALLBACK_BASE_URL", primary.BaseURL)
+	cfg.APIKey = envOr("LLM_FALLBACK_API_KEY", primary.APIKey)
+	return cfg, true
+}
+
+// VisionConfigFromEnv monta a Config do modelo com visão, usado no content_type "image", a partir
+// de LLM_VISION_MODEL. LLM_VISION_BASE_URL e LLM_VISION_API_KEY são opcionais e, se ausentes,
+// repetem o principal. Retorna false quando não há modelo de visão configurado.
+func VisionConfigFromEnv(primary Config) (Config, bool) {
+	model := os.Getenv("LLM_VISION_MODEL")
+	if model == "" {
+		return Config{}, false
+	}
+
+	cfg := primary
+	cfg.Model = model
+	cfg.BaseURL = envOr("LLM_VISION_BASE_URL", primary.BaseURL)
+	cfg.APIKey = envOr("LLM_VISION_API_KEY", primary.APIKey)
+	return cfg, true
+}
+
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration like 90s: %w", key, err)
+	}
+	return d, nil
+}
+
+func envInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	return n, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
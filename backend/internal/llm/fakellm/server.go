@@ -0,0 +1,210 @@
+// Package fakellm é um servidor de chat-completions compatível com a API da OpenAI que devolve
+// respostas roteirizadas, para exercitar a geração de flashcards sem chamar um provider real.
+//
+// Em testes:
+//
+//	fake := fakellm.New()
+//	srv := httptest.NewServer(fake)
+//	defer srv.Close()
+//	client := llm.NewClient(llm.Config{BaseURL: srv.URL + "/v1", Model: fakellm.ScenarioThink})
+//
+// O cenário é escolhido pelo nome do modelo da requisição (LLM_MODEL), a menos que haja
+// respostas enfileiradas com Enqueue, que têm prioridade e são consumidas em ordem.
+// Requisições com stream: true recebem o mesmo conteúdo em eventos SSE de poucos caracteres.
+package fakellm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// Cenários disponíveis, usados como nome do modelo.
+const (
+	ScenarioValid        = "valid"
+	ScenarioThink        = "think"
+	ScenarioFenced       = "fenced"
+	ScenarioMalformed    = "malformed"
+	ScenarioEmptyChoices = "empty-choices"
+	ScenarioServerError  = "server-error"
+	ScenarioRateLimited  = "rate-limited"
+	ScenarioUnauthorized = "unauthorized"
+)
+
+// Reply é uma resposta roteirizada. Content vira a mensagem do assistente num corpo de
+// chat-completions válido; Body, se preenchido, é enviado cru no lugar dele.
+type Reply struct {
+	Status  int
+	Content string
+	Body    string
+	Header  map[string]string
+}
+
+// Cards é o conteúdo fixo devolvido pelos cenários de sucesso.
+var Cards = []model.FlashcardRaw{
+	{Front: "Qual é a principal função do ventrículo esquerdo?", Back: "Bombear sangue oxigenado para a circulação sistêmica através da aorta."},
+	{Front: "O que é a pré-carga?", Back: "O grau de estiramento das fibras miocárdicas ao final da diástole, aproximado pelo volume diastólico final."},
+	{Front: "Qual é o marcapasso fisiológico do coração?", Back: "O nó sinoatrial, localizado no átrio direito."},
+}
+
+// Scenarios mapeia o nome de cada cenário para a resposta que ele produz.
+var Scenarios = map[string]Reply{
+	ScenarioValid:        {Status: http.StatusOK, Content: cardsJSON()},
+	ScenarioThink:        {Status: http.StatusOK, Content: "<think>\nO usuário quer flashcards de cardiologia. Vou montar um array JSON.\n</think>\n\n" + cardsJSON()},
+	ScenarioFenced:       {Status: http.StatusOK, Content: "<think>Formatando a saída.</think>\n```json\n" + cardsJSON() + "\n```"},
+	ScenarioMalformed:    {Status: http.StatusOK, Content: `[{"front": "Qual é o marcapasso fisiológico do coração?", "back": "O nó sinoatrial"`},
+	ScenarioEmptyChoices: {Status: http.StatusOK, Body: `{"choices": []}`},
+	ScenarioServerError:  {Status: http.StatusInternalServerError, Body: `{"error": {"message": "internal server error"}}`},
+	ScenarioRateLimited:  {Status: http.StatusTooManyRequests, Body: `{"error": {"message": "rate limit exceeded"}}`, Header: map[string]string{"Retry-After": "1"}},
+	ScenarioUnauthorized: {Status: http.StatusUnauthorized, Body: `{"error": {"message": "invalid api key"}}`},
+}
+
+// Server implementa http.Handler; use com httptest.NewServer ou http.ListenAndServe.
+type Server struct {
+	mu       sync.Mutex
+	queue    []Reply
+	fallback string
+	requests []llm.ChatRequest
+}
+
+// New cria um Server que, sem respostas enfileiradas, responde pelo cenário do nome do modelo
+// ou por ScenarioValid quando o modelo não é um cenário conhecido.
+func New() *Server {
+	return &Server{fallback: ScenarioValid}
+}
+
+// SetDefaultScenario troca o cenário usado quando o modelo da requisição não é um cenário conhecido.
+func (s *Server) SetDefaultScenario(name string) error {
+	if _, ok := Scenarios[name]; !ok {
+		return fmt.Errorf("unknown scenario %q", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = name
+	return nil
+}
+
+// Enqueue agenda respostas para as próximas requisições, em ordem, antes de qualquer cenário.
+func (s *Server) Enqueue(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, replies...)
+}
+
+// Requests devolve as requisições recebidas até agora, na ordem de chegada.
+func (s *Server) Requests() []llm.ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]llm.ChatRequest(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req llm.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBody(w, Reply{Status: http.StatusBadRequest, Body: `{"error": {"message": "invalid request body"}}`})
+		return
+	}
+
+	reply := s.next(req)
+	if req.Stream && reply.Body == "" && (reply.Status == 0 || reply.Status == http.StatusOK) {
+		writeStream(w, reply)
+		return
+	}
+	writeBody(w, reply)
+}
+
+// next registra a requisição e escolhe a resposta: fila, cenário do modelo ou cenário padrão.
+func (s *Server) next(req llm.ChatRequest) Reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+
+	if len(s.queue) > 0 {
+		reply := s.queue[0]
+		s.queue = s.queue[1:]
+		return reply
+	}
+	if reply, ok := Scenarios[req.Model]; ok {
+		return reply
+	}
+	return Scenarios[s.fallback]
+}
+
+func writeBody(w http.ResponseWriter, reply Reply) {
+	for k, v := range reply.Header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	body := reply.Body
+	if body == "" {
+		body = completionBody(reply.Content)
+	}
+
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// streamChunkRunes é o tamanho de cada pedaço do stream, pequeno para os cards chegarem partidos.
+const streamChunkRunes = 8
+
+// writeStream envia o conteúdo como um stream de chat-completions: um evento "data:" por pedaço
+// e o "[DONE]" no fim.
+func writeStream(w http.ResponseWriter, reply Reply) {
+	for k, v := range reply.Header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	runes := []rune(reply.Content)
+	for start := 0; start < len(runes); start += streamChunkRunes {
+		end := min(start+streamChunkRunes, len(runes))
+
+		var chunk llm.ChatStreamChunk
+		chunk.Choices = make([]struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		}, 1)
+		chunk.Choices[0].Delta.Content = string(runes[start:end])
+		raw, _ := json.Marshal(chunk)
+
+		fmt.Fprintf(w, "data: %s\n\n", raw)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func completionBody(content string) string {
+	var resp llm.ChatResponse
+	resp.Choices = append(resp.Choices, struct {
+		Message llm.Message `json:"message"`
+	}{Message: llm.Message{Role: "assistant", Content: content}})
+	raw, _ := json.Marshal(resp)
+	return string(raw)
+}
+
+func cardsJSON() string {
+	raw, _ := json.MarshalIndent(Cards, "", "  ")
+	return string(raw)
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// fallbackChatter usa o modelo reserva quando o circuit breaker do principal está aberto.
+type fallbackChatter struct {
+	primary  *Client
+	fallback Chatter
+}
+
+// WithFallback devolve um StreamChatter que tenta primary e, se o breaker dele estiver aberto
+// (inclusive se abriu durante esta chamada), repete a conversa em fallback.
+func WithFallback(primary *Client, fallback Chatter) StreamChatter {
+	return &fallbackChatter{primary: primary, fallback: fallback}
+}
+
+func (f *fallbackChatter) Chat(ctx context.Context, messages []Message) (string, error) {
+	content, err := f.primary.Chat(ctx, messages)
+	if err == nil {
+		return content, nil
+	}
+	if !errors.Is(err, ErrCircuitOpen) && !f.primary.CircuitOpen() {
+		return "", err
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	log.Printf("LLM %s indisponível (%v), usando modelo reserva", f.primary.Model(), err)
+	return f.fallback.Chat(ctx, messages)
+}
+
+// ChatStream faz o mesmo em modo stream. Se o principal já entregou algum pedaço, o erro volta
+// direto: repetir no reserva duplicaria o começo da resposta. Um reserva sem stream entrega
+// tudo de uma vez.
+func (f *fallbackChatter) ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error) {
+	delivered := false
+	content, err := f.primary.ChatStream(ctx, messages, func(delta string) {
+		delivered = true
+		onDelta(delta)
+	})
+	if err == nil {
+		return content, nil
+	}
+	if delivered || (!errors.Is(err, ErrCircuitOpen) && !f.primary.CircuitOpen()) {
+		return "", err
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	log.Printf("LLM %s indisponível (%v), usando modelo reserva", f.primary.Model(), err)
+	if streamer, ok := f.fallback.(StreamChatter); ok {
+		return streamer.ChatStream(ctx, messages, onDelta)
+	}
+	content, err = f.fallback.Chat(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	onDelta(content)
+	return content, nil
+}
@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubChatter struct {
+	calls int
+}
+
+func (s *stubChatter) Chat(ctx context.Context, messages []Message) (string, error) {
+	s.calls++
+	return "reserva", nil
+}
+
+func TestFallbackOnlyWhenBreakerOpens(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	primary := NewClient(Config{BaseURL: srv.URL, Model: "test", BreakerThreshold: 2})
+	reserve := &stubChatter{}
+	chat := WithFallback(primary, reserve)
+	messages := []Message{{Role: "user", Content: "hi"}}
+
+	// Primeira falha: o breaker ainda está fechado, o erro volta para quem chamou
+	if _, err := chat.Chat(context.Background(), messages); err == nil {
+		t.Fatal("expected the 502 to fail the call")
+	}
+	if reserve.calls != 0 {
+		t.Fatalf("fallback called %d times before the breaker opened", reserve.calls)
+	}
+
+	// Segunda falha abre o breaker durante a chamada, que então vai para a reserva
+	content, err := chat.Chat(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if content != "reserva" || reserve.calls != 1 {
+		t.Fatalf("content = %q after %d fallback calls, want the reserve answer", content, reserve.calls)
+	}
+}
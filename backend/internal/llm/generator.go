@@ -0,0 +1,366 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/ingestion"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/utils"
+)
+
+// Generator gera flashcards a partir de um tópico ou de um resumo enviado pelo usuário.
+// As opções da requisição (quantidade, tipos de pergunta, idioma) devem vir já com os padrões
+// aplicados (model.GenerationOptions.WithDefaults).
+type Generator interface {
+	GenerateFlashcards(ctx context.Context, req model.PromptRequest) (model.FlashcardsResponse, error)
+	GenerateFlashcardsFromSummary(ctx context.Context, req model.SummaryRequest) (model.FlashcardsResponse, error)
+}
+
+type flashcardGenerator struct {
+	chat   Chatter
+	vision Chatter
+}
+
+// ErrNoVisionModel indica um pedido com imagem sem modelo de visão configurado (LLM_VISION_MODEL).
+var ErrNoVisionModel = errors.New("image input is not enabled: no vision-capable model configured")
+
+// NewGenerator cria um Generator que monta os prompts e delega a chamada ao modelo para chat.
+// Conversas com imagem vão para vision, que pode ser nil quando não há modelo de visão;
+// nesse caso pedidos com imagem falham com ErrNoVisionModel.
+func NewGenerator(chat, vision Chatter) Generator {
+	return &flashcardGenerator{chat: chat, vision: vision}
+}
+
+// Limite de tokens do modelo (deixando margem de segurança para a resposta)
+const maxTokensPerRequest = 120000
+
+// GenerateFlashcards gera os flashcards pedidos sobre o tópico informado.
+func (g *flashcardGenerator) GenerateFlashcards(ctx context.Context, req model.PromptRequest) (model.FlashcardsResponse, error) {
+	difficulty := difficultyDescriptions[req.Level.OrDefault()]
+
+	opts := req.GenerationOptions.WithDefaults()
+	systemPrompt := buildSystemPrompt(promptSpec{
+		count:      opts.Count,
+		source:     fmt.Sprintf("based on the topic of %s", req.Prompt),
+		difficulty: difficulty,
+		opts:       opts,
+	})
+
+	flashcardsResponse, err := g.complete(ctx, []Message{{Role: "user", Content: systemPrompt}}, opts.Count, nil)
+	if err != nil {
+		return model.FlashcardsResponse{}, err
+	}
+	reportProgress(ctx, 1, 1)
+
+	log.Println("flashcardsResponse: ", flashcardsResponse)
+
+	return flashcardsResponse, nil
+}
+
+// GenerateFlashcardsFromSummary gera flashcards a partir do conteúdo enviado.
+// It supports text, PDF, and image content types.
+func (g *flashcardGenerator) GenerateFlashcardsFromSummary(ctx context.Context, req model.SummaryRequest) (model.FlashcardsResponse, error) {
+	difficulty := difficultyDescriptions[req.Level.OrDefault()]
+
+	opts := req.GenerationOptions.WithDefaults()
+
+	if req.ContentType == "image" && g.vision == nil {
+		return model.FlashcardsResponse{}, ErrNoVisionModel
+	}
+	content, err := sourceContent(req)
+	if err != nil {
+		return model.FlashcardsResponse{}, err
+	}
+
+	// Para conteúdo de texto (incluindo o extraído do PDF), aplicamos chunking se necessário
+	if req.ContentType != "image" && utils.EstimateTokenCount(content) > maxTokensPerRequest {
+		log.Printf("Conteúdo muito grande (%d tokens estimados), aplicando chunking", utils.EstimateTokenCount(content))
+		return g.generateWithChunking(ctx, content, req.ContentType, difficulty, opts, maxTokensPerRequest)
+	}
+
+	// Para conteúdo normal ou não-texto, processa normalmente
+	return g.generateSingle(ctx, content, req.ContentType, difficulty, opts)
+}
+
+// sourceContent devolve o que vai para o prompt: o texto (para documentos, extraído, com as seções
+// marcadas como "[Página N]", "[Slide N]", ...) ou o data URI da imagem, já validada para não gastar tokens com
+// arquivo inválido. O conteúdo vem de req.Data (arquivo de POST /sources) ou de req.Content
+// (texto ou base64). Erros de ingestion.IsRejected são culpa do arquivo enviado.
+func sourceContent(req model.SummaryRequest) (string, error) {
+	switch {
+	case ingestion.IsDocument(req.ContentType):
+		data := req.Data
+		if data == nil && ingestion.IsBinary(req.ContentType) {
+			var err error
+			if data, err = ingestion.DecodeBase64(req.Content); err != nil {
+				return "", err
+			}
+		} else if data == nil {
+			data = []byte(req.Content)
+		}
+		return extractText(req.ContentType, data)
+	case req.ContentType == "image":
+		var img ingestion.Image
+		var err error
+		if req.Data != nil {
+			img, err = ingestion.ValidateImage(req.Data, "")
+		} else {
+			img, err = ingestion.DecodeImage(req.Content)
+		}
+		if err != nil {
+			return "", err
+		}
+		return img.DataURI(), nil
+	}
+	if req.Data != nil {
+		return string(req.Data), nil
+	}
+	return req.Content, nil
+}
+
+func extractText(contentType string, data []byte) (string, error) {
+	doc, err := ingestion.Extract(contentType, data)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Documento %s extraído: %d seções com texto", contentType, len(doc.Sections))
+	return doc.Text(), nil
+}
+
+// generateWithChunking processa conteúdo grande dividindo em chunks
+func (g *flashcardGenerator) generateWithChunking(ctx context.Context, content string, contentType string, difficulty string, opts model.GenerationOptions, maxTokens int) (model.FlashcardsResponse, error) {
+	chunks := utils.ChunkContent(content, maxTokens)
+	log.Printf("Dividindo conteúdo em %d chunks", len(chunks))
+
+	// Os cards de um chunk só são repassados quando ele termina bem: um chunk que falha no meio
+	// fica fora do merge, e os índices enviados ao cliente precisam bater com a lista final.
+	// O merge fica só com os opts.Count primeiros cards; os que serão descartados não são repassados.
+	onCard := cardsHook(ctx)
+	ctx = WithCards(ctx, nil)
+	sent := 0
+
+	var allResponses []model.FlashcardsResponse
+	perChunk := allocateCards(opts.Count, len(chunks))
+
+	for i, chunk := range chunks {
+		log.Printf("Processando chunk %d/%d (%d tokens estimados, %d flashcards)", i+1, len(chunks), utils.EstimateTokenCount(chunk), perChunk[i])
+
+		// Em transcrições, cada chunk começa numa seção e leva as próprias âncoras de tempo
+		var anchors []string
+		if isTranscript(contentType) {
+			anchors = ingestion.TimestampAnchors(chunk)
+		}
+
+		// Ajusta o prompt para chunking
+		systemPrompt := buildSystemPrompt(promptSpec{
+			count:      perChunk[i],
+			source:     fmt.Sprintf("baseado no resumo/texto que o usuário forneceu (parte %d de %d)", i+1, len(chunks)),
+			material:   " extracted from this part of the text",
+			difficulty: difficulty,
+			opts:       opts,
+			timestamps: len(anchors) > 0,
+		})
+
+		messageContent := fmt.Sprintf("Com base na seguinte parte do resumo/texto (parte %d de %d), gere %d flashcards médicos:\n\n%s",
+			i+1, len(chunks), perChunk[i], chunk)
+
+		response, err := g.complete(ctx, []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: messageContent},
+		}, perChunk[i], anchors)
+		if err != nil {
+			// Cancelamento do cliente interrompe tudo; outros erros só pulam o chunk
+			if ctx.Err() != nil {
+				return model.FlashcardsResponse{}, ctx.Err()
+			}
+			log.Printf("Erro ao processar chunk %d: %v", i+1, err)
+			// Continua com os outros chunks mesmo se um falhar
+			reportProgress(ctx, i+1, len(chunks))
+			continue
+		}
+
+		allResponses = append(allResponses, response)
+		for _, card := range response.Flashcards {
+			if onCard == nil || sent >= opts.Count {
+				break
+			}
+			sent++
+			onCard(card)
+		}
+		reportProgress(ctx, i+1, len(chunks))
+	}
+
+	if len(allResponses) == 0 {
+		return model.FlashcardsResponse{}, errors.New("falha ao processar todos os chunks do conteúdo")
+	}
+
+	// Combina todas as respostas em uma única
+	finalResponse := utils.MergeFlashcardResponses(allResponses, opts.Count)
+	log.Printf("Gerados %d flashcards total a partir de %d chunks", len(finalResponse.Flashcards), len(chunks))
+
+	return finalResponse, nil
+}
+
+// documentNames descrevem cada formato de documento nos prompts.
+var documentNames = map[string]string{
+	"pdf":      "PDF",
+	"docx":     "documento do Word",
+	"pptx":     "conjunto de slides de aula (com as notas do apresentador)",
+	"epub":     "livro EPUB",
+	"html":     "página da web",
+	"markdown": "texto em Markdown",
+}
+
+// generateSingle processa conteúdo que cabe em uma única requisição. Para documentos, content já é
+// o texto extraído; para "image", o data URI da imagem validada.
+func (g *flashcardGenerator) generateSingle(ctx context.Context, content string, contentType string, difficulty string, opts model.GenerationOptions) (model.FlashcardsResponse, error) {
+	spec := promptSpec{count: opts.Count, difficulty: difficulty, opts: opts}
+	var messageContent string
+	var parts []ContentPart
+	var anchors []string
+
+	// Create different prompts based on content type
+	switch contentType {
+	case "text":
+		spec.source = "baseado no resumo/texto que o usuário forneceu"
+		spec.material = " extracted from the provided text"
+		messageContent = fmt.Sprintf("Com base no seguinte resumo/texto, gere %d flashcards médicos:\n\n%s", opts.Count, content)
+
+	case "pdf", "docx", "pptx", "epub", "html", "markdown":
+		name := documentNames[contentType]
+		spec.source = "baseado no texto extraído do " + name + " que o usuário enviou"
+		spec.material = " extracted from the " + contentType + " content"
+		messageContent = fmt.Sprintf("Com base no seguinte texto extraído de um %s (as seções estão marcadas entre colchetes), gere %d flashcards médicos:\n\n%s", name, opts.Count, content)
+
+	case "srt", "vtt":
+		anchors = ingestion.TimestampAnchors(content)
+		spec.source = "baseado na transcrição da aula gravada que o usuário enviou"
+		spec.material = " extracted from the lecture transcript"
+		spec.timestamps = len(anchors) > 0
+		messageContent = fmt.Sprintf("Com base na seguinte transcrição de uma aula gravada (cada trecho começa com o momento da aula entre colchetes), gere %d flashcards médicos:\n\n%s", opts.Count, content)
+
+	case "image":
+		spec.source = "baseado na imagem que o usuário enviou"
+		spec.material = " extracted from the image content"
+		parts = []ContentPart{
+			TextPart(fmt.Sprintf("Com base na imagem a seguir, gere %d flashcards médicos:", opts.Count)),
+			ImagePart(content),
+		}
+
+	default:
+		return model.FlashcardsResponse{}, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+
+	response, err := g.complete(ctx, []Message{
+		{Role: "system", Content: buildSystemPrompt(spec)},
+		{Role: "user", Content: messageContent, Parts: parts},
+	}, opts.Count, anchors)
+	if err != nil {
+		return model.FlashcardsResponse{}, err
+	}
+	reportProgress(ctx, 1, 1)
+	return response, nil
+}
+
+// complete chama o modelo e converte a resposta (sem o bloco <think> e o cercado ```json) em flashcards.
+// Se o contexto tem um CardFunc (WithCards), os cards são repassados conforme ficam prontos.
+// Cards além de limit (o modelo às vezes gera a mais) são descartados. Conversas com imagem
+// vão para o modelo de visão. anchors são as âncoras de tempo da transcrição enviada (nil para
+// outros conteúdos); o timestamp de cada card é acertado para uma delas (anchorCard).
+func (g *flashcardGenerator) complete(ctx context.Context, messages []Message, limit int, anchors []string) (model.FlashcardsResponse, error) {
+	chat := g.chat
+	if HasImages(messages) {
+		if g.vision == nil {
+			return model.FlashcardsResponse{}, ErrNoVisionModel
+		}
+		chat = g.vision
+	}
+
+	onCard := cardsHook(ctx)
+	if onCard != nil {
+		sent := 0
+		next := onCard
+		onCard = func(card model.Flashcard) {
+			if sent < limit {
+				sent++
+				next(card)
+			}
+		}
+	}
+	streamer, canStream := chat.(StreamChatter)
+
+	var rawContent string
+	var err error
+	if onCard != nil && canStream {
+		var parser cardStreamParser
+		rawContent, err = streamer.ChatStream(ctx, messages, func(delta string) {
+			for _, raw := range parser.Write(delta) {
+				card := model.Flashcard{QuestionText: raw.Front, AnswerText: raw.Back}
+				anchorCard(&card, raw.Timestamp, anchors)
+				onCard(card)
+			}
+		})
+	} else {
+		rawContent, err = chat.Chat(ctx, messages)
+	}
+	if err != nil {
+		return model.FlashcardsResponse{}, err
+	}
+
+	// Remove <think></think> tags
+	cleanContent := utils.StripThinkTagAlternative(rawContent)
+
+	response, err := utils.ParseFlashcardsResponse(cleanContent)
+	if err != nil {
+		return model.FlashcardsResponse{}, err
+	}
+	if len(response.Flashcards) > limit {
+		response.Flashcards = response.Flashcards[:limit]
+	}
+	for i := range response.Flashcards {
+		card := &response.Flashcards[i]
+		var timestamp string
+		if card.SourceTimestamp != nil {
+			timestamp = *card.SourceTimestamp
+		}
+		anchorCard(card, timestamp, anchors)
+	}
+	if onCard != nil && !canStream {
+		for _, card := range response.Flashcards {
+			onCard(card)
+		}
+	}
+	return response, nil
+}
+
+// isTranscript diz se o conteúdo é uma transcrição de aula, cujas seções são âncoras de tempo.
+func isTranscript(contentType string) bool {
+	return contentType == "srt" || contentType == "vtt"
+}
+
+// anchorCard grava em card.SourceTimestamp a âncora de onde o card saiu: a que o modelo indicou
+// ou, se ele inventou um tempo, a última âncora antes dele; sem indicação válida, a primeira do
+// trecho. Sem âncoras (conteúdo que não é transcrição), o card fica sem timestamp.
+func anchorCard(card *model.Flashcard, timestamp string, anchors []string) {
+	card.SourceTimestamp = nil
+	if len(anchors) == 0 {
+		return
+	}
+
+	anchor := anchors[0]
+	// As âncoras estão em ordem e no formato fixo HH:MM:SS, então dá para comparar como texto
+	timestamp = strings.Trim(strings.TrimSpace(timestamp), "[]")
+	if len(timestamp) == len(anchor) {
+		for _, a := range anchors {
+			if a > timestamp {
+				break
+			}
+			anchor = a
+		}
+	}
+	card.SourceTimestamp = &anchor
+}
@@ -0,0 +1,295 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/llm/fakellm"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// newFakeGenerator sobe o fakellm e devolve um Generator apontado para ele, com backoff curto
+// para os testes de repetição não esperarem.
+func newFakeGenerator(t *testing.T, scenario string, maxRetries int) (llm.Generator, *fakellm.Server) {
+	t.Helper()
+	fake := fakellm.New()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client := llm.NewClient(llm.Config{
+		BaseURL:          srv.URL + "/v1",
+		Model:            scenario,
+		MaxRetries:       maxRetries,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		BreakerThreshold: -1,
+	})
+	return llm.NewGenerator(client, nil), fake
+}
+
+func topicRequest() model.PromptRequest {
+	return model.PromptRequest{
+		Prompt:            "cardiologia",
+		GenerationOptions: model.GenerationOptions{Count: len(fakellm.Cards)},
+	}
+}
+
+func assertCards(t *testing.T, got []model.Flashcard) {
+	t.Helper()
+	if len(got) != len(fakellm.Cards) {
+		t.Fatalf("got %d cards, want %d", len(got), len(fakellm.Cards))
+	}
+	for i, card := range got {
+		if card.QuestionText != fakellm.Cards[i].Front || card.AnswerText != fakellm.Cards[i].Back {
+			t.Errorf("card %d = %q / %q, want %q / %q", i, card.QuestionText, card.AnswerText, fakellm.Cards[i].Front, fakellm.Cards[i].Back)
+		}
+	}
+}
+
+func TestGenerateFlashcardsParsesScriptedReplies(t *testing.T) {
+	for _, scenario := range []string{fakellm.ScenarioValid, fakellm.ScenarioThink, fakellm.ScenarioFenced} {
+		t.Run(scenario, func(t *testing.T) {
+			gen, fake := newFakeGenerator(t, scenario, 0)
+			resp, err := gen.GenerateFlashcards(context.Background(), topicRequest())
+			if err != nil {
+				t.Fatalf("GenerateFlashcards: %v", err)
+			}
+			if reqs := fake.Requests(); len(reqs) != 1 || reqs[0].Model != scenario {
+				t.Fatalf("expected one request for model %q, got %+v", scenario, reqs)
+			}
+			assertCards(t, resp.Flashcards)
+		})
+	}
+}
+
+func TestGenerateFlashcardsStreamsCards(t *testing.T) {
+	for _, scenario := range []string{fakellm.ScenarioThink, fakellm.ScenarioFenced} {
+		t.Run(scenario, func(t *testing.T) {
+			gen, fake := newFakeGenerator(t, scenario, 0)
+
+			var streamed []model.Flashcard
+			ctx := llm.WithCards(context.Background(), func(card model.Flashcard) {
+				streamed = append(streamed, card)
+			})
+			resp, err := gen.GenerateFlashcards(ctx, topicRequest())
+			if err != nil {
+				t.Fatalf("GenerateFlashcards: %v", err)
+			}
+			if reqs := fake.Requests(); len(reqs) != 1 || !reqs[0].Stream {
+				t.Fatalf("expected one streamed request, got %+v", reqs)
+			}
+			assertCards(t, streamed)
+			assertCards(t, resp.Flashcards)
+		})
+	}
+}
+
+func TestGenerateFlashcardsMalformedJSON(t *testing.T) {
+	gen, _ := newFakeGenerator(t, fakellm.ScenarioMalformed, 0)
+	if _, err := gen.GenerateFlashcards(context.Background(), topicRequest()); err == nil {
+		t.Fatal("expected an error for a truncated JSON array")
+	}
+}
+
+func TestGenerateFlashcardsSurfacesAPIErrors(t *testing.T) {
+	tests := []struct {
+		scenario string
+		want     string
+	}{
+		{fakellm.ScenarioEmptyChoices, "empty choices"},
+		{fakellm.ScenarioServerError, "(500)"},
+		{fakellm.ScenarioRateLimited, "(429)"},
+		{fakellm.ScenarioUnauthorized, "(401)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			gen, _ := newFakeGenerator(t, tt.scenario, 0)
+			_, err := gen.GenerateFlashcards(context.Background(), topicRequest())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueuedRepliesTakePriority(t *testing.T) {
+	gen, fake := newFakeGenerator(t, fakellm.ScenarioServerError, 0)
+	fake.Enqueue(fakellm.Reply{Content: `[{"front": "Pergunta", "back": "Resposta"}]`})
+
+	resp, err := gen.GenerateFlashcardsFromSummary(context.Background(), model.SummaryRequest{
+		Content:           "Um resumo curto.",
+		ContentType:       "text",
+		GenerationOptions: model.GenerationOptions{Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("GenerateFlashcardsFromSummary: %v", err)
+	}
+	if len(resp.Flashcards) != 1 || resp.Flashcards[0].QuestionText != "Pergunta" {
+		t.Fatalf("cards = %+v, want the enqueued card", resp.Flashcards)
+	}
+	if _, err := gen.GenerateFlashcards(context.Background(), topicRequest()); err == nil {
+		t.Fatal("expected the scenario to answer once the queue is empty")
+	}
+}
+
+func TestGenerateFlashcardsRetriesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply fakellm.Reply
+	}{
+		{"server error", fakellm.Scenarios[fakellm.ScenarioServerError]},
+		{"rate limited", fakellm.Reply{Status: http.StatusTooManyRequests, Body: `{"error": {"message": "rate limit exceeded"}}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, fake := newFakeGenerator(t, fakellm.ScenarioValid, 2)
+			fake.Enqueue(tt.reply, tt.reply)
+
+			resp, err := gen.GenerateFlashcards(context.Background(), topicRequest())
+			if err != nil {
+				t.Fatalf("GenerateFlashcards: %v", err)
+			}
+			if n := len(fake.Requests()); n != 3 {
+				t.Errorf("got %d requests, want 3 (two failures and a success)", n)
+			}
+			assertCards(t, resp.Flashcards)
+		})
+	}
+}
+
+func TestGenerateFlashcardsGivesUpAfterRetries(t *testing.T) {
+	for _, scenario := range []string{fakellm.ScenarioServerError, fakellm.ScenarioRateLimited} {
+		t.Run(scenario, func(t *testing.T) {
+			gen, fake := newFakeGenerator(t, scenario, 1)
+			if scenario == fakellm.ScenarioRateLimited {
+				// Sem Retry-After, para o teste não esperar o segundo inteiro do cenário
+				reply := fakellm.Reply{Status: http.StatusTooManyRequests, Body: `{"error": {"message": "rate limit exceeded"}}`}
+				fake.Enqueue(reply, reply)
+			}
+
+			_, err := gen.GenerateFlashcards(context.Background(), topicRequest())
+			var apiErr *llm.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *llm.APIError", err)
+			}
+			if want := fakellm.Scenarios[scenario].Status; apiErr.StatusCode != want {
+				t.Errorf("status = %d, want %d", apiErr.StatusCode, want)
+			}
+			if n := len(fake.Requests()); n != 2 {
+				t.Errorf("got %d requests, want 2", n)
+			}
+		})
+	}
+}
+
+func TestGenerateFlashcardsDoesNotRetryClientErrors(t *testing.T) {
+	gen, fake := newFakeGenerator(t, fakellm.ScenarioUnauthorized, 3)
+	_, err := gen.GenerateFlashcards(context.Background(), topicRequest())
+	var apiErr *llm.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want a 401 APIError", err)
+	}
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+// transcriptSRT vira duas seções, [00:00:01] e [00:01:05]
+const transcriptSRT = "1\n00:00:01,000 --> 00:00:04,000\nA insuficiência cardíaca é uma síndrome clínica.\n\n" +
+	"2\n00:01:01,000 --> 00:01:04,000\nAgora o ritmo cardíaco.\n\n" +
+	"3\n00:01:05,000 --> 00:01:09,000\nO nó sinoatrial é o marcapasso fisiológico.\n"
+
+func TestGenerateFromTranscriptAnchorsTimestamps(t *testing.T) {
+	gen, fake := newFakeGenerator(t, fakellm.ScenarioValid, 0)
+	fake.Enqueue(fakellm.Reply{Content: `[
+		{"front": "O que é insuficiência cardíaca?", "back": "Uma síndrome clínica.", "timestamp": "00:00:01"},
+		{"front": "Qual é o marcapasso do coração?", "back": "O nó sinoatrial.", "timestamp": "[00:01:30]"},
+		{"front": "Pergunta sem tempo", "back": "Resposta.", "timestamp": "depois"}
+	]`})
+
+	resp, err := gen.GenerateFlashcardsFromSummary(context.Background(), model.SummaryRequest{
+		Content:           transcriptSRT,
+		ContentType:       "srt",
+		GenerationOptions: model.GenerationOptions{Count: 3},
+	})
+	if err != nil {
+		t.Fatalf("GenerateFlashcardsFromSummary: %v", err)
+	}
+
+	// Tempo inventado vai para a âncora anterior; tempo inválido, para a primeira do trecho
+	want := []string{"00:00:01", "00:01:05", "00:00:01"}
+	if len(resp.Flashcards) != len(want) {
+		t.Fatalf("got %d cards, want %d", len(resp.Flashcards), len(want))
+	}
+	for i, card := range resp.Flashcards {
+		if card.SourceTimestamp == nil || *card.SourceTimestamp != want[i] {
+			t.Errorf("card %d timestamp = %v, want %s", i, card.SourceTimestamp, want[i])
+		}
+	}
+
+	prompt := fake.Requests()[0].Messages[0].Content
+	if !strings.Contains(prompt, "'timestamp'") {
+		t.Errorf("system prompt does not ask for timestamps: %s", prompt)
+	}
+}
+
+func TestGenerateFromTextIgnoresTimestampLines(t *testing.T) {
+	gen, fake := newFakeGenerator(t, fakellm.ScenarioValid, 0)
+	fake.Enqueue(fakellm.Reply{Content: `[{"front": "Pergunta", "back": "Resposta.", "timestamp": "00:01:00"}]`})
+
+	resp, err := gen.GenerateFlashcardsFromSummary(context.Background(), model.SummaryRequest{
+		Content:           "[00:01:00]\nUm resumo que por acaso tem uma linha de tempo.",
+		ContentType:       "text",
+		GenerationOptions: model.GenerationOptions{Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("GenerateFlashcardsFromSummary: %v", err)
+	}
+	if len(resp.Flashcards) != 1 || resp.Flashcards[0].SourceTimestamp != nil {
+		t.Fatalf("cards = %+v, want one card without timestamp", resp.Flashcards)
+	}
+	if prompt := fake.Requests()[0].Messages[0].Content; strings.Contains(prompt, "'timestamp'") {
+		t.Errorf("system prompt asks for timestamps on plain text: %s", prompt)
+	}
+}
+
+func TestGenerateWithChunkingStreamsOnlySuccessfulChunks(t *testing.T) {
+	gen, fake := newFakeGenerator(t, fakellm.ScenarioValid, 0)
+	// O primeiro chunk traz um card completo e depois quebra; o segundo dá certo
+	fake.Enqueue(
+		fakellm.Reply{Content: `[{"front": "Card perdido", "back": "Do chunk que falhou."}, {"front": "Pela met`},
+		fakellm.Reply{Content: `[{"front": "Pergunta 1", "back": "Resposta 1."}, {"front": "Pergunta 2", "back": "Resposta 2."}]`},
+	)
+
+	paragraph := strings.Repeat("O nó sinoatrial é o marcapasso fisiológico. ", 7000)
+	var streamed []model.Flashcard
+	ctx := llm.WithCards(context.Background(), func(card model.Flashcard) {
+		streamed = append(streamed, card)
+	})
+	resp, err := gen.GenerateFlashcardsFromSummary(ctx, model.SummaryRequest{
+		Content:           paragraph + "\n\n" + paragraph,
+		ContentType:       "text",
+		GenerationOptions: model.GenerationOptions{Count: 4},
+	})
+	if err != nil {
+		t.Fatalf("GenerateFlashcardsFromSummary: %v", err)
+	}
+	if n := len(fake.Requests()); n != 2 {
+		t.Fatalf("got %d requests, want one per chunk (2)", n)
+	}
+
+	if len(resp.Flashcards) != 2 || len(streamed) != len(resp.Flashcards) {
+		t.Fatalf("streamed %d cards and returned %d, want the 2 cards of the successful chunk", len(streamed), len(resp.Flashcards))
+	}
+	for i := range streamed {
+		if streamed[i].QuestionText != resp.Flashcards[i].QuestionText {
+			t.Errorf("streamed card %d = %q, final list has %q", i, streamed[i].QuestionText, resp.Flashcards[i].QuestionText)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Message é uma mensagem da conversa. Mensagens só de texto usam Content; mensagens multimodais
+// (texto + imagens) usam Parts e são enviadas no formato de array da API da OpenAI.
+type Message struct {
+	Role    string
+	Content string
+	Parts   []ContentPart
+}
+
+// Tipos de ContentPart.
+const (
+	PartText     = "text"
+	PartImageURL = "image_url"
+)
+
+// ContentPart é um pedaço de uma mensagem multimodal: texto ou imagem.
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL aponta a imagem por URL ou data URI ("data:image/png;base64,...").
+// Detail ("low", "high", "auto") é opcional.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// TextPart cria um pedaço de texto.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: PartText, Text: text}
+}
+
+// ImagePart cria um pedaço de imagem a partir de uma URL ou data URI.
+func ImagePart(url string) ContentPart {
+	return ContentPart{Type: PartImageURL, ImageURL: &ImageURL{URL: url}}
+}
+
+// HasImages diz se a conversa tem alguma imagem, isto é, se precisa de um modelo com visão.
+func HasImages(messages []Message) bool {
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			if p.Type == PartImageURL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type messageJSON struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalJSON envia content como string ou, quando há Parts, como array de pedaços.
+func (m Message) MarshalJSON() ([]byte, error) {
+	var content any = m.Content
+	if len(m.Parts) > 0 {
+		content = m.Parts
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(messageJSON{Role: m.Role, Content: raw})
+}
+
+// UnmarshalJSON aceita os dois formatos de content. Com array, Content recebe o texto dos
+// pedaços de texto, para quem só lê texto (como o fakellm).
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw messageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Message{Role: raw.Role}
+
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+	if raw.Content[0] != '[' {
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+
+	if err := json.Unmarshal(raw.Content, &m.Parts); err != nil {
+		return err
+	}
+	var texts []string
+	for _, p := range m.Parts {
+		if p.Type == PartText {
+			texts = append(texts, p.Text)
+		}
+	}
+	m.Content = strings.Join(texts, "\n")
+	return nil
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// ProgressFunc recebe quantas etapas da geração já terminaram de um total
+// (1 de 1 numa chamada simples, um por chunk quando o conteúdo é dividido).
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress registra no contexto uma função chamada pelo Generator a cada etapa concluída.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, done, total int) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(done, total)
+	}
+}
+
+// CardFunc recebe cada flashcard assim que o modelo termina de escrevê-lo, antes do fim da resposta.
+type CardFunc func(card model.Flashcard)
+
+type cardsKey struct{}
+
+// WithCards registra no contexto uma função chamada a cada card gerado. Com ela o Generator usa o
+// modo stream do provider quando disponível; sem stream, os cards chegam todos ao fim da chamada.
+func WithCards(ctx context.Context, fn CardFunc) context.Context {
+	return context.WithValue(ctx, cardsKey{}, fn)
+}
+
+func cardsHook(ctx context.Context) CardFunc {
+	fn, _ := ctx.Value(cardsKey{}).(CardFunc)
+	return fn
+}
@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// promptSpec descreve um pedido de geração para buildSystemPrompt.
+type promptSpec struct {
+	count int
+	// source diz de onde vêm os cards, ex.: "based on the topic of X"
+	source string
+	// material completa "testable material", ex.: " extracted from the provided text"
+	material   string
+	difficulty string
+	opts       model.GenerationOptions
+	// timestamps pede a âncora de tempo de cada card, para transcrições de aula
+	timestamps bool
+}
+
+// difficultyDescriptions descreve cada nível para o modelo.
+var difficultyDescriptions = map[model.Difficulty]string{
+	model.DifficultyBeginner:     "nível básico",
+	model.DifficultyIntermediate: "nível intermediário",
+	model.DifficultyAdvanced:     "nível avançado",
+}
+
+// questionTypeDescriptions explica ao modelo cada tipo de pergunta aceito na requisição.
+var questionTypeDescriptions = map[string]string{
+	model.QuestionDefinition:            "definitions of terms and concepts",
+	model.QuestionMechanism:             "mechanisms (physiology and pathophysiology)",
+	model.QuestionClinicalVignette:      "clinical vignettes (a short patient case followed by a question)",
+	model.QuestionDifferentialDiagnosis: "differential diagnosis (how to tell similar conditions apart)",
+	model.QuestionPharmacology:          "pharmacology (drugs, mechanisms of action, indications and adverse effects)",
+}
+
+// languageInstructions é a instrução final de idioma de cada idioma aceito.
+var languageInstructions = map[string]string{
+	model.LanguagePortuguese: "Gere tudo isso em português brasileiro",
+	model.LanguageEnglish:    "Write everything in English",
+	model.LanguageSpanish:    "Genera todo en español",
+}
+
+// buildSystemPrompt monta o prompt de sistema comum a todos os tipos de geração.
+func buildSystemPrompt(spec promptSpec) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Generate %d flashcards designed for medical school students to practice for exams, %s. ", spec.count, spec.source)
+	fmt.Fprintf(&b, "The flashcards should be at %s, appropriate for medical school standards. ", spec.difficulty)
+	b.WriteString("Each flashcard should have a 'front' (a question or term) and a 'back' (a detailed, accurate answer or definition). ")
+	fmt.Fprintf(&b, "The content should be concise yet comprehensive, focusing on key concepts, clinical relevance, and testable material%s. ", spec.material)
+
+	if len(spec.opts.QuestionTypes) == 0 {
+		b.WriteString("Ensure variety in the types of questions (e.g., definitions, mechanisms, clinical scenarios, diagnostics) to aid efficient learning. ")
+	} else {
+		types := make([]string, 0, len(spec.opts.QuestionTypes))
+		for _, t := range spec.opts.QuestionTypes {
+			types = append(types, questionTypeDescriptions[t])
+		}
+		fmt.Fprintf(&b, "Use only these types of questions, spread evenly across the flashcards: %s. ", strings.Join(types, "; "))
+	}
+
+	if spec.timestamps {
+		b.WriteString("The text is a lecture transcript split into blocks that start with a timestamp in brackets, like [00:12:34]. ")
+		b.WriteString("Format the output as a JSON array, with each object containing 'front', 'back' and 'timestamp' fields, where 'timestamp' is the timestamp of the block the flashcard was derived from, without the brackets (e.g. \"00:12:34\"). ")
+	} else {
+		b.WriteString("Format the output as a JSON array, with each object containing 'front' and 'back' fields. ")
+	}
+
+	language, ok := languageInstructions[spec.opts.Language]
+	if !ok {
+		language = languageInstructions[model.LanguagePortuguese]
+	}
+	b.WriteString(language)
+
+	return b.String()
+}
+
+// allocateCards divide count cards entre os chunks: a divisão inteira para todos e o resto para
+// os primeiros. Cada chunk pede pelo menos um card; se há mais chunks que cards, o excedente
+// some no merge, que fica com os count primeiros.
+func allocateCards(count, chunks int) []int {
+	perChunk := make([]int, chunks)
+	for i := range perChunk {
+		perChunk[i] = count / chunks
+		if i < count%chunks {
+			perChunk[i]++
+		}
+		if perChunk[i] == 0 {
+			perChunk[i] = 1
+		}
+	}
+	return perChunk
+}
@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksCacheTTL é por quanto tempo as chaves baixadas são usadas sem consultar a fonte de novo.
+	jwksCacheTTL = 10 * time.Minute
+	// jwksMinRefreshInterval limita recargas forçadas por "kid" desconhecido, para um token forjado
+	// não virar uma enxurrada de requisições ao endpoint JWKS.
+	jwksMinRefreshInterval = time.Minute
+	jwksFetchTimeout       = 10 * time.Second
+)
+
+var errUnknownKeyID = errors.New("unknown signing key (kid)")
+
+// jwk é uma chave pública no formato JSON Web Key (RFC 7517).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC e OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwksCache mantém as chaves públicas do JWKS do Supabase (URL ou arquivo local) indexadas por "kid".
+// As chaves são recarregadas quando o cache expira ou quando chega um token assinado com um
+// "kid" desconhecido, o que cobre a rotação de chaves sem reiniciar o servidor. A busca roda fora
+// do lock e uma só por vez: requisições que precisam dela esperam a que está em andamento, e as
+// demais seguem com as chaves atuais.
+type jwksCache struct {
+	url    string
+	file   string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	// fetching é fechado quando a busca em andamento termina; nil se não há busca
+	fetching chan struct{}
+	fetchErr error
+}
+
+func newJWKSCache(url string, file string) *jwksCache {
+	return &jwksCache{
+		url:    url,
+		file:   file,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// key retorna a chave pública para o "kid" do token. Tokens sem "kid" são aceitos se o JWKS tiver uma única chave.
+func (c *jwksCache) key(kid string) (interface{}, error) {
+	c.mu.RLock()
+	key, found := c.lookup(kid)
+	fresh := time.Since(c.fetchedAt) < jwksCacheTTL
+	c.mu.RUnlock()
+	if found {
+		if !fresh {
+			// Cache vencido: responde com a chave conhecida e recarrega sem segurar a requisição
+			go func() {
+				if err := c.refresh(true); err != nil {
+					log.Printf("Erro ao recarregar JWKS: %v", err)
+				}
+			}()
+		}
+		return key, nil
+	}
+
+	if err := c.refresh(true); err != nil {
+		log.Printf("Erro ao carregar JWKS: %v", err)
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if key, found = c.lookup(kid); found {
+		return key, nil
+	}
+	return nil, errUnknownKeyID
+}
+
+func (c *jwksCache) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// refresh recarrega o JWKS, ou espera a recarga que já está em andamento. Com limited, e já havendo
+// chaves carregadas, não busca de novo antes de jwksMinRefreshInterval desde a última tentativa;
+// sem chaves (a busca da inicialização falhou, por exemplo) tenta sempre.
+func (c *jwksCache) refresh(limited bool) error {
+	c.mu.Lock()
+	if fetching := c.fetching; fetching != nil {
+		c.mu.Unlock()
+		<-fetching
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.fetchErr
+	}
+	if limited && len(c.keys) > 0 && time.Since(c.lastAttempt) < jwksMinRefreshInterval {
+		c.mu.Unlock()
+		return nil
+	}
+	fetching := make(chan struct{})
+	c.fetching = fetching
+	c.lastAttempt = time.Now()
+	c.mu.Unlock()
+
+	keys, err := c.fetch()
+
+	c.mu.Lock()
+	if err == nil {
+		c.keys = keys
+		c.fetchedAt = time.Now()
+	}
+	c.fetchErr = err
+	c.fetching = nil
+	c.mu.Unlock()
+	close(fetching)
+	return err
+}
+
+// fetch baixa e decodifica o JWKS, sem mexer no cache.
+func (c *jwksCache) fetch() (map[string]interface{}, error) {
+	data, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			log.Printf("Ignorando chave %q do JWKS: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (c *jwksCache) load() ([]byte, error) {
+	if c.file != "" {
+		return os.ReadFile(c.file)
+	}
+
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint returned %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// publicKey converte a JWK para a chave pública correspondente de crypto/*.
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
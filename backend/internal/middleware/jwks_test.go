@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer serve um JWKS com uma chave Ed25519 "k1"; enquanto down for true, responde 503.
+func jwksServer(t *testing.T) (*httptest.Server, *atomic.Bool, *atomic.Int32) {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := fmt.Sprintf(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","use":"sig","x":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(pub))
+
+	var down atomic.Bool
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &down, &hits
+}
+
+func TestJWKSRetriesAfterStartupFailure(t *testing.T) {
+	srv, down, _ := jwksServer(t)
+	down.Store(true)
+
+	cache := newJWKSCache(srv.URL, "")
+	if err := cache.refresh(false); err == nil {
+		t.Fatal("expected the startup refresh to fail")
+	}
+
+	down.Store(false)
+	if _, err := cache.key("k1"); err != nil {
+		t.Fatalf("first request after the source recovered: %v", err)
+	}
+}
+
+func TestJWKSLimitsRefreshesForUnknownKid(t *testing.T) {
+	srv, _, hits := jwksServer(t)
+
+	cache := newJWKSCache(srv.URL, "")
+	if err := cache.refresh(false); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.key("forged"); err != errUnknownKeyID {
+			t.Fatalf("key(forged) error = %v, want errUnknownKeyID", err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (unknown kids are rate limited)", n)
+	}
+}
+
+func TestJWKSServesStaleKeyWhileRefreshing(t *testing.T) {
+	srv, down, hits := jwksServer(t)
+
+	cache := newJWKSCache(srv.URL, "")
+	if err := cache.refresh(false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cache vencido e fonte fora do ar: a chave conhecida continua valendo
+	cache.mu.Lock()
+	cache.fetchedAt = time.Now().Add(-2 * jwksCacheTTL)
+	cache.lastAttempt = time.Now().Add(-2 * jwksMinRefreshInterval)
+	cache.mu.Unlock()
+	down.Store(true)
+
+	if _, err := cache.key("k1"); err != nil {
+		t.Fatalf("stale key: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for hits.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if hits.Load() < 2 {
+		t.Fatal("expected a background refresh of the stale cache")
+	}
+	if _, err := cache.key("k1"); err != nil {
+		t.Fatalf("key after a failed background refresh: %v", err)
+	}
+}
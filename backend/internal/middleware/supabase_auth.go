@@ -1,63 +1,142 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// asymmetricMethods são os algoritmos aceitos para tokens verificados pelo JWKS.
+var asymmetricMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// authConfig reúne as variáveis de ambiente da validação de JWT.
+type authConfig struct {
+	secret   string   // SUPABASE_JWT_SECRET: segredo compartilhado (HS256), modo legado
+	jwksURL  string   // SUPABASE_JWKS_URL: ex. https://<projeto>.supabase.co/auth/v1/.well-known/jwks.json
+	jwksFile string   // SUPABASE_JWKS_FILE: JWKS em arquivo local, alternativa à URL
+	issuer   string   // SUPABASE_JWT_ISSUER: valor esperado em "iss" (opcional)
+	audience string   // SUPABASE_JWT_AUDIENCE: valor esperado em "aud" (padrão "authenticated")
+	roles    []string // SUPABASE_JWT_ROLES: valores aceitos em "role", separados por vírgula (padrão "authenticated")
+}
+
+func loadAuthConfig() authConfig {
+	cfg := authConfig{
+		secret:   os.Getenv("SUPABASE_JWT_SECRET"),
+		jwksURL:  os.Getenv("SUPABASE_JWKS_URL"),
+		jwksFile: os.Getenv("SUPABASE_JWKS_FILE"),
+		issuer:   os.Getenv("SUPABASE_JWT_ISSUER"),
+		audience: os.Getenv("SUPABASE_JWT_AUDIENCE"),
+	}
+	if cfg.audience == "" {
+		cfg.audience = "authenticated"
+	}
+
+	roles := os.Getenv("SUPABASE_JWT_ROLES")
+	if roles == "" {
+		roles = "authenticated"
+	}
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			cfg.roles = append(cfg.roles, role)
+		}
+	}
+
+	return cfg
+}
+
+// SupabaseAuth valida o JWT do Supabase enviado no header Authorization.
+// Tokens assimétricos (RS256/ES256...) são verificados com o JWKS do projeto, escolhendo a chave
+// pelo "kid"; tokens HS256 continuam aceitos quando SUPABASE_JWT_SECRET está configurado.
 func SupabaseAuth() gin.HandlerFunc {
-	secret := os.Getenv("SUPABASE_JWT_SECRET")
-	if secret == "" {
-		panic("SUPABASE_JWT_SECRET is required")
+	cfg := loadAuthConfig()
+
+	var jwks *jwksCache
+	if cfg.jwksURL != "" || cfg.jwksFile != "" {
+		jwks = newJWKSCache(cfg.jwksURL, cfg.jwksFile)
+		if err := jwks.refresh(false); err != nil {
+			// Não impede a subida do servidor: as chaves são buscadas de novo na primeira requisição
+			log.Printf("Erro ao carregar JWKS na inicialização: %v", err)
+		}
+	}
+	if jwks == nil && cfg.secret == "" {
+		panic("SUPABASE_JWKS_URL, SUPABASE_JWKS_FILE or SUPABASE_JWT_SECRET is required")
+	}
+
+	var validMethods []string
+	if jwks != nil {
+		validMethods = append(validMethods, asymmetricMethods...)
+	}
+	if cfg.secret != "" {
+		validMethods = append(validMethods, jwt.SigningMethodHS256.Alg())
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithAudience(cfg.audience),
 	}
-  
+	if cfg.issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(cfg.issuer))
+	}
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			return []byte(cfg.secret), nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return jwks.key(kid)
+	}
+
 	return func(c *gin.Context) {
-	  auth := c.GetHeader("Authorization")
-	  if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid Authorization header"})
-		return
-	  }
-  
-	  tokenStr := strings.TrimPrefix(auth, "Bearer ")
-	  token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		// Ensure HS256
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		  return nil, jwt.ErrTokenMalformed
-		}
-		return []byte(secret), nil
-	  })
-	  if err != nil || !token.Valid {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	  }
-  
-	  // Extract claims (map claims)
-	  claims, ok := token.Claims.(jwt.MapClaims)
-	  if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid claims"})
-		return
-	  }
-  
-	  // Optional: Check the "aud" claim matches your Supabase project's API URL
-	  // Optional: Check exp, iat are valid (jwt.Parse does this by default)
-  
-	  // Grab the user UUID from "sub"
-	  userID, ok := claims["sub"].(string)
-	  if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid sub claim"})
-		return
-	  }
-  
-	  // Grab the user email from "email" claim
-	  userEmail, _ := claims["email"].(string) // Optional, might not always be present
-  
-	  // Set in context for handlers to use
-	  c.Set("userID", userID)
-	  c.Set("userEmail", userEmail)
-	  c.Next()
-	}
-  }
\ No newline at end of file
+		auth := c.GetHeader("Authorization")
+		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid Authorization header"})
+			return
+		}
+
+		tokenStr := strings.TrimPrefix(auth, "Bearer ")
+		token, err := jwt.Parse(tokenStr, keyFunc, parserOptions...)
+		if err != nil || !token.Valid {
+			if err == nil {
+				err = errors.New("invalid token")
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Extract claims (map claims)
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid claims"})
+			return
+		}
+
+		// Only end users may call the API (e.g. rejects "anon" and "service_role" keys)
+		role, _ := claims["role"].(string)
+		if !slices.Contains(cfg.roles, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "role not allowed"})
+			return
+		}
+
+		// Grab the user UUID from "sub"
+		userID, ok := claims["sub"].(string)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid sub claim"})
+			return
+		}
+
+		// Grab the user email from "email" claim
+		userEmail, _ := claims["email"].(string) // Optional, might not always be present
+
+		// Set in context for handlers to use
+		c.Set("userID", userID)
+		c.Set("userEmail", userEmail)
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Difficulty é o nível dos cards de um set. Os valores gravados são sempre os canônicos abaixo;
+// na entrada também são aceitos os apelidos easy/medium/hard (ver ParseDifficulty).
+type Difficulty string
+
+const (
+	DifficultyBeginner     Difficulty = "beginner"
+	DifficultyIntermediate Difficulty = "intermediate"
+	DifficultyAdvanced     Difficulty = "advanced"
+)
+
+// DefaultDifficulty é usada quando a geração não informa o nível.
+const DefaultDifficulty = DifficultyIntermediate
+
+// ErrInvalidDifficulty indica um nível fora dos valores e apelidos aceitos.
+var ErrInvalidDifficulty = errors.New("difficulty must be one of: beginner, intermediate, advanced (or easy, medium, hard)")
+
+// difficultyAliases mapeia cada valor aceito, canônico ou apelido, para o canônico.
+var difficultyAliases = map[string]Difficulty{
+	"beginner":     DifficultyBeginner,
+	"intermediate": DifficultyIntermediate,
+	"advanced":     DifficultyAdvanced,
+	"easy":         DifficultyBeginner,
+	"medium":       DifficultyIntermediate,
+	"hard":         DifficultyAdvanced,
+}
+
+// ParseDifficulty normaliza um nível (sem diferenciar maiúsculas) para o valor canônico.
+func ParseDifficulty(value string) (Difficulty, error) {
+	d, ok := difficultyAliases[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return "", ErrInvalidDifficulty
+	}
+	return d, nil
+}
+
+// OrDefault devolve o próprio nível ou DefaultDifficulty quando ele não foi informado.
+func (d Difficulty) OrDefault() Difficulty {
+	if d == "" {
+		return DefaultDifficulty
+	}
+	return d
+}
+
+// UnmarshalJSON aceita os apelidos e rejeita valores desconhecidos; string vazia ou null
+// deixam o nível em branco.
+func (d *Difficulty) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil || *value == "" {
+		*d = ""
+		return nil
+	}
+
+	parsed, err := ParseDifficulty(*value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 type FlashcardRaw struct {
 	Front string `json:"front"`
 	Back  string `json:"back"`
+	// Timestamp é a âncora da transcrição ("00:12:34") de onde o card saiu, quando há
+	Timestamp string `json:"timestamp,omitempty"`
 }
 
 type Flashcard struct {
@@ -17,6 +19,8 @@ type Flashcard struct {
 	CardOrder int `json:"card_order" db:"card_order"`
 	QuestionText string `json:"question_text" db:"question_text"`
 	AnswerText string `json:"answer_text" db:"answer_text"`
+	// SourceTimestamp é o momento da aula gravada de onde o card foi gerado (HH:MM:SS)
+	SourceTimestamp *string `json:"source_timestamp,omitempty" db:"source_timestamp"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -26,14 +30,59 @@ type FlashcardsResponse struct {
 }
 
 type PromptRequest struct {
-	Prompt string `json:"prompt"`
-	Level string `json:"level"`
+	Prompt string `json:"prompt" binding:"required"`
+	Level Difficulty `json:"level"`
+	GenerationOptions
 }
 
+// SummaryRequest pede flashcards a partir de material de estudo: enviado em Content ou, para
+// arquivos já enviados em POST /sources, por SourceID. Em Content, text, html, markdown, srt e vtt
+// vão como texto; pdf, docx, pptx, epub e image, em base64.
 type SummaryRequest struct {
-	Content     string  `json:"content" binding:"required"`
-	ContentType string  `json:"content_type" binding:"required,oneof=text pdf image"`
-	Level       string  `json:"level"`
-	FileName    *string `json:"file_name,omitempty"`
+	Content     string     `json:"content" binding:"required_without=SourceID"`
+	ContentType string     `json:"content_type" binding:"required_without=SourceID,omitempty,oneof=text pdf image docx pptx epub html markdown srt vtt"`
+	SourceID    *uuid.UUID `json:"source_id,omitempty"`
+	Level       Difficulty `json:"level"`
+	FileName    *string    `json:"file_name,omitempty"`
+	// Data é o conteúdo já em bytes, preenchido pelo handler a partir de SourceID
+	Data []byte `json:"-"`
+	GenerationOptions
 }
 
+
+// FlashcardInput é um card escrito pelo usuário, sem passar pela geração.
+type FlashcardInput struct {
+	QuestionText string `json:"question_text" binding:"required"`
+	AnswerText   string `json:"answer_text" binding:"required"`
+}
+
+// FlashcardPatch altera apenas os campos informados de um card.
+type FlashcardPatch struct {
+	QuestionText *string `json:"question_text" binding:"omitempty,min=1"`
+	AnswerText   *string `json:"answer_text" binding:"omitempty,min=1"`
+}
+
+// ReorderRequest traz todos os cards do set na nova ordem.
+type ReorderRequest struct {
+	FlashcardIDs []uuid.UUID `json:"flashcard_ids" binding:"required,min=1,max=1000"`
+}
+
+// MoveRequest move os cards informados para o fim de outro set do usuário.
+type MoveRequest struct {
+	FlashcardIDs []uuid.UUID `json:"flashcard_ids" binding:"required,min=1,max=1000"`
+	TargetSetID  uuid.UUID   `json:"target_set_id" binding:"required"`
+}
+
+// CopyRequest copia os cards informados para um set existente (TargetSetID)
+// ou para um set novo criado com o tópico NewSetTopic. Exatamente um dos dois deve ser informado.
+type CopyRequest struct {
+	FlashcardIDs []uuid.UUID `json:"flashcard_ids" binding:"required,min=1,max=1000"`
+	TargetSetID  *uuid.UUID  `json:"target_set_id" binding:"required_without=NewSetTopic,excluded_with=NewSetTopic"`
+	NewSetTopic  *string     `json:"new_set_topic" binding:"omitempty,min=1"`
+}
+
+// CopyResult devolve o set de destino e os cards criados nele.
+type CopyResult struct {
+	TargetSetID uuid.UUID   `json:"target_set_id"`
+	Flashcards  []Flashcard `json:"flashcards"`
+}
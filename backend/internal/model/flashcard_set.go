@@ -7,20 +7,49 @@ import (
 )
 
 type FlashcardSet struct {
-	ID uuid.UUID `json:"id"`
-	UserID uuid.UUID `json:"user_id"`
-	Topic string `json:"topic"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uuid.UUID   `json:"id"`
+	UserID     uuid.UUID   `json:"user_id"`
+	Topic      string      `json:"topic"`
+	Difficulty *Difficulty `json:"difficulty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
 }
 
-
+// FlashcardSetSummary é a linha do dashboard: o set com os totais calculados no banco,
+// sem carregar os cards.
+type FlashcardSetSummary struct {
+	FlashcardSet
+	FlashcardCount int        `json:"flashcard_count"`
+	DueCount       int        `json:"due_count"`
+	NewCount       int        `json:"new_count"`
+	LastStudiedAt  *time.Time `json:"last_studied_at"`
+}
 
 type FlashcardSetWithFlashcards struct {
-	ID        string            `json:"id"`
-	UserID    string            `json:"user_id"`
-	Topic     string            `json:"topic"`
-	CreatedAt string            `json:"created_at"`
-	UpdatedAt string            `json:"updated_at"`
+	ID         string      `json:"id"`
+	UserID     string      `json:"user_id"`
+	Topic      string      `json:"topic"`
+	Difficulty *Difficulty `json:"difficulty"`
+	CreatedAt  string      `json:"created_at"`
+	UpdatedAt  string      `json:"updated_at"`
 	Flashcards []Flashcard `json:"flashcards"`
 }
+
+// FlashcardSetInput cria um set manualmente, opcionalmente já com os cards.
+type FlashcardSetInput struct {
+	Topic      string           `json:"topic" binding:"required"`
+	Difficulty Difficulty       `json:"difficulty"`
+	Flashcards []FlashcardInput `json:"flashcards" binding:"omitempty,max=500,dive"`
+}
+
+// FlashcardSetPatch altera apenas os campos informados de um set. Difficulty aceita os mesmos
+// apelidos da criação, e "" tira o nível do set.
+type FlashcardSetPatch struct {
+	Topic      *string     `json:"topic" binding:"omitempty,min=1"`
+	Difficulty *Difficulty `json:"difficulty"`
+}
+
+// FlashcardSetFilter restringe as listagens de sets; campos nil não filtram.
+type FlashcardSetFilter struct {
+	Difficulty *Difficulty
+}
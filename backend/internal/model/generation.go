@@ -0,0 +1,42 @@
+package model
+
+// Limites da quantidade de cards por geração. O teto real de cada usuário vem do plano (User.MaxCards).
+const (
+	DefaultCardCount = 10
+	MaxCardCount     = 50
+)
+
+// Tipos de pergunta aceitos em GenerationOptions.QuestionTypes.
+const (
+	QuestionDefinition            = "definition"
+	QuestionMechanism             = "mechanism"
+	QuestionClinicalVignette      = "clinical_vignette"
+	QuestionDifferentialDiagnosis = "differential_diagnosis"
+	QuestionPharmacology          = "pharmacology"
+)
+
+// Idiomas aceitos em GenerationOptions.Language.
+const (
+	LanguagePortuguese = "pt-BR"
+	LanguageEnglish    = "en"
+	LanguageSpanish    = "es"
+)
+
+// GenerationOptions são os ajustes comuns aos pedidos de geração. Campos vazios assumem
+// os padrões em WithDefaults (10 cards, tipos variados, português).
+type GenerationOptions struct {
+	Count         int      `json:"count,omitempty" binding:"omitempty,min=1,max=50"`
+	QuestionTypes []string `json:"question_types,omitempty" binding:"omitempty,max=5,unique,dive,oneof=definition mechanism clinical_vignette differential_diagnosis pharmacology"`
+	Language      string   `json:"language,omitempty" binding:"omitempty,oneof=pt-BR en es"`
+}
+
+// WithDefaults preenche os campos não informados.
+func (o GenerationOptions) WithDefaults() GenerationOptions {
+	if o.Count == 0 {
+		o.Count = DefaultCardCount
+	}
+	if o.Language == "" {
+		o.Language = LanguagePortuguese
+	}
+	return o
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Estados de um job de geração.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Tipos de job. O payload de JobKindPrompt é um PromptRequest.
+const (
+	JobKindPrompt = "prompt"
+)
+
+// GenerationJob é uma geração de flashcards processada em segundo plano pelos workers.
+type GenerationJob struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Kind           string          `json:"kind"`
+	Payload        json.RawMessage `json:"-"`
+	Status         string          `json:"status"`
+	Progress       int             `json:"progress"`
+	FlashcardSetID *uuid.UUID      `json:"flashcard_set_id"`
+	Error          *string         `json:"error"`
+	Attempts       int             `json:"attempts"`
+	StartedAt      *time.Time      `json:"started_at"`
+	FinishedAt     *time.Time      `json:"finished_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Finished indica se o job chegou a um estado final.
+func (j GenerationJob) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled
+}
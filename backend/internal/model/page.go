@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Campos aceitos em ?sort= nas listagens.
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortTopic     = "topic"
+	SortCardOrder = "card_order"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ErrInvalidCursor indica um cursor que não foi gerado pela API ou que não combina com sort/order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest descreve uma página de uma listagem ordenada por Sort e desempatada pelo id.
+type PageRequest struct {
+	Limit  int
+	Sort   string
+	Desc   bool
+	Cursor *Cursor
+}
+
+// Cursor aponta para o último item da página anterior. Para o cliente ele é opaco:
+// só trafega codificado por Encode.
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Encode serializa o cursor em base64 URL-safe, pronto para ir em next_cursor.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor faz o caminho inverso de Encode. Value precisa ter o formato do campo de Sort
+// (instante RFC 3339 ou inteiro), senão o erro só apareceria no cast feito pelo banco.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort == "" || c.ID == uuid.Nil || !validCursorValue(c.Sort, c.Value) {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func validCursorValue(sort, value string) bool {
+	switch sort {
+	case SortCreatedAt, SortUpdatedAt:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	case SortCardOrder:
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	default:
+		return true
+	}
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CardReview guarda o estado de agendamento de um flashcard para um usuário.
+type CardReview struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	FlashcardID    uuid.UUID  `json:"flashcard_id"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	Stability      float64    `json:"stability"`
+	Difficulty     float64    `json:"difficulty"`
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ScheduleState é o retrato do agendamento de um card, guardado antes e depois de cada revisão.
+type ScheduleState struct {
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	Stability      float64    `json:"stability"`
+	Difficulty     float64    `json:"difficulty"`
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+}
+
+// State retorna o estado de agendamento atual do card.
+func (r CardReview) State() ScheduleState {
+	return ScheduleState{
+		EaseFactor:     r.EaseFactor,
+		IntervalDays:   r.IntervalDays,
+		Repetitions:    r.Repetitions,
+		Stability:      r.Stability,
+		Difficulty:     r.Difficulty,
+		DueAt:          r.DueAt,
+		LastReviewedAt: r.LastReviewedAt,
+	}
+}
+
+// ReviewRequest é a nota (0 a 5, escala SM-2) dada pelo usuário ao responder um card.
+type ReviewRequest struct {
+	Grade          *int `json:"grade" binding:"required,min=0,max=5"`
+	ResponseTimeMs *int `json:"response_time_ms" binding:"omitempty,min=0"`
+}
+
+// ReviewAnswer é uma resposta a um card, dada avulsa ou dentro de uma sessão de estudo.
+type ReviewAnswer struct {
+	FlashcardID    uuid.UUID
+	Grade          int
+	ResponseTimeMs *int
+	SessionID      *uuid.UUID
+	AnsweredAt     time.Time
+	// NotBefore é o menor horário aceito para AnsweredAt, ex.: o início da sessão de estudo
+	NotBefore time.Time
+}
+
+// ReviewLog é o registro de uma resposta do usuário a um card. O histórico é somente-inserção.
+type ReviewLog struct {
+	ID             uuid.UUID      `json:"id"`
+	UserID         uuid.UUID      `json:"user_id"`
+	FlashcardID    uuid.UUID      `json:"flashcard_id"`
+	SessionID      *uuid.UUID     `json:"session_id,omitempty"`
+	Grade          int            `json:"grade"`
+	ResponseTimeMs *int           `json:"response_time_ms,omitempty"`
+	Scheduler      string         `json:"scheduler"`
+	StateBefore    *ScheduleState `json:"state_before"`
+	StateAfter     *ScheduleState `json:"state_after"`
+	ReviewedAt     time.Time      `json:"reviewed_at"`
+}
+
+// DueCard é um card na fila de estudo do dia; DueAt é nulo para cards nunca estudados.
+type DueCard struct {
+	Flashcard
+	Topic string     `json:"topic"`
+	IsNew bool       `json:"is_new"`
+	DueAt *time.Time `json:"due_at"`
+}
+
+// DueQueue é a fila de estudo do dia: revisões vencidas (pela data de vencimento) seguidas dos cards novos.
+type DueQueue struct {
+	Cards       []DueCard `json:"cards"`
+	ReviewCount int       `json:"review_count"`
+	NewCount    int       `json:"new_count"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSettings guarda as preferências de estudo do usuário.
+type UserSettings struct {
+	UserID           uuid.UUID `json:"user_id"`
+	Scheduler        string    `json:"scheduler"`
+	DesiredRetention float64   `json:"desired_retention"`
+	FSRSWeights      []float64 `json:"fsrs_weights,omitempty"`
+	NewCardsPerDay   int       `json:"new_cards_per_day"`
+	ReviewsPerDay    int       `json:"reviews_per_day"`
+	// FSRSOptimizedAt é quando os pesos foram ajustados ao histórico pela última vez
+	FSRSOptimizedAt *time.Time `json:"fsrs_optimized_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// UpdateSettingsRequest contém os campos que o usuário pode alterar; campos ausentes são mantidos.
+type UpdateSettingsRequest struct {
+	Scheduler        *string  `json:"scheduler" binding:"omitempty,oneof=sm2 fsrs"`
+	DesiredRetention *float64 `json:"desired_retention" binding:"omitempty,gte=0.7,lte=0.97"`
+	NewCardsPerDay   *int     `json:"new_cards_per_day" binding:"omitempty,min=0,max=9999"`
+	ReviewsPerDay    *int     `json:"reviews_per_day" binding:"omitempty,min=0,max=9999"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxSourceBytes limita o tamanho de um arquivo enviado em POST /sources. Imagens têm o limite
+// menor de ingestion.MaxImageBytes.
+const MaxSourceBytes = 25 << 20
+
+// Source é um arquivo de material de estudo enviado pelo usuário e guardado no blob store.
+// Pedidos de geração por resumo o referenciam por SourceID em vez de mandar o conteúdo.
+type Source struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	FileName string    `json:"file_name"`
+	// ContentType é o content_type de geração detectado no conteúdo ("text", "pdf", "image", "docx", ...)
+	ContentType string    `json:"content_type"`
+	MIMEType    string    `json:"mime_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudySession agrupa as respostas dadas pelo usuário em uma rodada de estudo de um ou mais sets.
+type StudySession struct {
+	ID                uuid.UUID   `json:"id"`
+	UserID            uuid.UUID   `json:"user_id"`
+	SetIDs            []uuid.UUID `json:"set_ids"`
+	StartedAt         time.Time   `json:"started_at"`
+	EndedAt           *time.Time  `json:"ended_at"`
+	AnswersCount      int         `json:"answers_count"`
+	CorrectCount      int         `json:"correct_count"`
+	AverageResponseMs *int        `json:"average_response_ms"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// SessionSummary é o resumo devolvido ao encerrar uma sessão.
+type SessionSummary struct {
+	AnswersCount      int     `json:"answers_count"`
+	CorrectCount      int     `json:"correct_count"`
+	Accuracy          float64 `json:"accuracy"`
+	AverageResponseMs *int    `json:"average_response_ms"`
+	DurationSeconds   int     `json:"duration_seconds"`
+}
+
+type StartSessionRequest struct {
+	SetIDs []uuid.UUID `json:"set_ids" binding:"required,min=1,max=100"`
+}
+
+type SessionAnswerRequest struct {
+	FlashcardID    uuid.UUID  `json:"flashcard_id" binding:"required"`
+	Grade          *int       `json:"grade" binding:"required,min=0,max=5"`
+	ResponseTimeMs *int       `json:"response_time_ms" binding:"omitempty,min=0"`
+	AnsweredAt     *time.Time `json:"answered_at"`
+}
@@ -6,6 +6,28 @@ type User struct {
 	ID uuid.UUID `json:"id"`
 	Email string `json:"email"`
 	PasswordHash string `json:"-"`
+	Plan string `json:"plan"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// Planos disponíveis (coluna users.plan).
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+)
+
+// maxCardsByPlan é quantos cards cada plano pode pedir numa única geração.
+var maxCardsByPlan = map[string]int{
+	PlanFree: 20,
+	PlanPro:  MaxCardCount,
+}
+
+// MaxCards devolve o limite de cards por geração do plano do usuário; planos desconhecidos
+// ficam com o limite do gratuito.
+func (u User) MaxCards() int {
+	if limit, ok := maxCardsByPlan[u.Plan]; ok {
+		return limit
+	}
+	return maxCardsByPlan[PlanFree]
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "database/sql"
+
+// requireAffected devolve sql.ErrNoRows quando um UPDATE/DELETE não encontrou a linha,
+// para os serviços tratarem do mesmo jeito que um SELECT vazio.
+func requireAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -4,15 +4,27 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type FlashcardRepository interface {
     Create(ctx context.Context, fc *model.Flashcard) error
+    CreateBatch(ctx context.Context, cards []model.Flashcard) error
+    GetByID(ctx context.Context, id uuid.UUID) (model.Flashcard, error)
+    Update(ctx context.Context, fc *model.Flashcard) error
+    Delete(ctx context.Context, id uuid.UUID) error
+    Append(ctx context.Context, fc *model.Flashcard) error
+    Reorder(ctx context.Context, setID uuid.UUID, orderedIDs []uuid.UUID) error
+    Move(ctx context.Context, sourceSetID uuid.UUID, targetSetID uuid.UUID, ids []uuid.UUID) error
+    Copy(ctx context.Context, sourceSetID uuid.UUID, targetSetID uuid.UUID, ids []uuid.UUID) ([]model.Flashcard, error)
     GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]model.Flashcard, error)
-    GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string) ([]model.Flashcard, error)
+    GetAllBySetIDs(ctx context.Context, setIDs []uuid.UUID) (map[uuid.UUID][]model.Flashcard, error)
+    ListBySetID(ctx context.Context, setID uuid.UUID, page model.PageRequest) ([]model.Flashcard, string, error)
+    GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string, page model.PageRequest) ([]model.Flashcard, string, error)
 }
 
 type flashcardRepo struct {
@@ -27,22 +39,148 @@ func (r *flashcardRepo) Create(ctx context.Context, fc *model.Flashcard) error {
     query := `INSERT INTO flashcards (flashcard_set_id, card_order, question_text, answer_text, created_at, updated_at)
               VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING id`
     
-    err := r.db.QueryRowContext(ctx, query, fc.FlashcardSetID, fc.CardOrder, fc.QuestionText, fc.AnswerText).
+    err := conn(ctx, r.db).QueryRowContext(ctx, query, fc.FlashcardSetID, fc.CardOrder, fc.QuestionText, fc.AnswerText).
         Scan(&fc.ID)
     
     return err
 }
 
+// batchSize limita quantas linhas vão em cada INSERT de CreateBatch.
+const batchSize = 1000
+
+// CreateBatch insere os cards com um INSERT multi-linha por lote (unnest de arrays), em vez de um
+// round-trip por card. Os IDs são gerados aqui, então cards[i].ID corresponde sempre a cards[i];
+// created_at e updated_at são preenchidos com os valores gravados pelo banco.
+// Os lotes rodam na transação do contexto ou em uma transação própria.
+func (r *flashcardRepo) CreateBatch(ctx context.Context, cards []model.Flashcard) error {
+    if len(cards) == 0 {
+        return nil
+    }
+
+    query := `INSERT INTO flashcards (id, flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at)
+              SELECT id, set_id, card_order, question, answer, NULLIF(source_timestamp, ''), NOW(), NOW()
+              FROM unnest($1::uuid[], $2::uuid[], $3::int[], $4::text[], $5::text[], $6::text[])
+                  AS c(id, set_id, card_order, question, answer, source_timestamp)
+              RETURNING id, created_at, updated_at`
+
+    return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+        for start := 0; start < len(cards); start += batchSize {
+            end := min(start+batchSize, len(cards))
+            batch := cards[start:end]
+
+            ids := make([]string, len(batch))
+            setIDs := make([]string, len(batch))
+            orders := make([]int64, len(batch))
+            questions := make([]string, len(batch))
+            answers := make([]string, len(batch))
+            timestamps := make([]string, len(batch))
+            index := make(map[uuid.UUID]int, len(batch))
+            for i := range batch {
+                if batch[i].ID == uuid.Nil {
+                    batch[i].ID = uuid.New()
+                }
+                index[batch[i].ID] = i
+                ids[i] = batch[i].ID.String()
+                setIDs[i] = batch[i].FlashcardSetID.String()
+                orders[i] = int64(batch[i].CardOrder)
+                questions[i] = batch[i].QuestionText
+                answers[i] = batch[i].AnswerText
+                if batch[i].SourceTimestamp != nil {
+                    timestamps[i] = *batch[i].SourceTimestamp
+                }
+            }
+
+            rows, err := conn(ctx, r.db).QueryContext(ctx, query,
+                pq.Array(ids), pq.Array(setIDs), pq.Array(orders), pq.Array(questions), pq.Array(answers), pq.Array(timestamps))
+            if err != nil {
+                return err
+            }
+            for rows.Next() {
+                var id uuid.UUID
+                var createdAt, updatedAt time.Time
+                if err := rows.Scan(&id, &createdAt, &updatedAt); err != nil {
+                    rows.Close()
+                    return err
+                }
+                if i, ok := index[id]; ok {
+                    batch[i].CreatedAt = createdAt
+                    batch[i].UpdatedAt = updatedAt
+                }
+            }
+            rows.Close()
+            if err := rows.Err(); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+func (r *flashcardRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Flashcard, error) {
+    query := `SELECT id, flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at
+              FROM flashcards
+              WHERE id = $1`
+
+    var fc model.Flashcard
+    err := conn(ctx, r.db).QueryRowContext(ctx, query, id).
+        Scan(&fc.ID, &fc.FlashcardSetID, &fc.CardOrder, &fc.QuestionText, &fc.AnswerText, &fc.SourceTimestamp, &fc.CreatedAt, &fc.UpdatedAt)
+
+    return fc, err
+}
+
+// Update grava pergunta e resposta do card e renova o updated_at dele e do set, na mesma transação.
+func (r *flashcardRepo) Update(ctx context.Context, fc *model.Flashcard) error {
+    query := `UPDATE flashcards SET question_text = $2, answer_text = $3, updated_at = NOW()
+              WHERE id = $1
+              RETURNING flashcard_set_id, updated_at`
+
+    return r.inTx(ctx, func(ctx context.Context, tx querier) error {
+        if err := tx.QueryRowContext(ctx, query, fc.ID, fc.QuestionText, fc.AnswerText).Scan(&fc.FlashcardSetID, &fc.UpdatedAt); err != nil {
+            return err
+        }
+        return touchSets(ctx, tx, fc.FlashcardSetID)
+    })
+}
+
+// GetAllBySetIDs carrega os cards de vários sets numa única query, agrupados por set e em card_order.
+// Sets sem cards não aparecem no mapa.
+func (r *flashcardRepo) GetAllBySetIDs(ctx context.Context, setIDs []uuid.UUID) (map[uuid.UUID][]model.Flashcard, error) {
+    result := make(map[uuid.UUID][]model.Flashcard, len(setIDs))
+    if len(setIDs) == 0 {
+        return result, nil
+    }
+
+    query := `SELECT id, flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at
+              FROM flashcards
+              WHERE flashcard_set_id = ANY($1::uuid[])
+              ORDER BY flashcard_set_id, card_order`
+
+    rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(uuidStrings(setIDs)))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var fc model.Flashcard
+        if err := rows.Scan(&fc.ID, &fc.FlashcardSetID, &fc.CardOrder, &fc.QuestionText, &fc.AnswerText, &fc.SourceTimestamp, &fc.CreatedAt, &fc.UpdatedAt); err != nil {
+            return nil, err
+        }
+        result[fc.FlashcardSetID] = append(result[fc.FlashcardSetID], fc)
+    }
+    return result, rows.Err()
+}
+
 func (r *flashcardRepo) GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]model.Flashcard, error) {
     // Use a simpler query without explicit casting to avoid prepared statement issues
-    query := `SELECT id, flashcard_set_id, card_order, question_text, answer_text, created_at, updated_at 
+    query := `SELECT id, flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at 
               FROM flashcards 
               WHERE flashcard_set_id = $1 
               ORDER BY card_order`
     
     log.Printf("Executing query for flashcard set ID: %s", setID.String())
     
-    rows, err := r.db.QueryContext(ctx, query, setID)
+    rows, err := conn(ctx, r.db).QueryContext(ctx, query, setID)
     if err != nil {
         log.Printf("Error executing query: %v", err)
         return nil, err
@@ -58,7 +196,7 @@ func (r *flashcardRepo) GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]m
         // Explicitly log the scan operation
         log.Printf("Scanning row %d for flashcard set: %s", rowCount, setID.String())
         
-        if err := rows.Scan(&fc.ID, &fc.FlashcardSetID, &fc.CardOrder, &fc.QuestionText, &fc.AnswerText, &fc.CreatedAt, &fc.UpdatedAt); err != nil {
+        if err := rows.Scan(&fc.ID, &fc.FlashcardSetID, &fc.CardOrder, &fc.QuestionText, &fc.AnswerText, &fc.SourceTimestamp, &fc.CreatedAt, &fc.UpdatedAt); err != nil {
             log.Printf("Error scanning row %d: %v", rowCount, err)
             return nil, err
         }
@@ -77,30 +215,80 @@ func (r *flashcardRepo) GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]m
     return flashcards, nil
 }
 
-// 4. Get all flashcards by topic (for a specific user)
-func (r *flashcardRepo) GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string) ([]model.Flashcard, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT f.id, f.flashcard_set_id, f.card_order, f.question_text, f.answer_text, f.created_at, f.updated_at
+// setFlashcardSortKeys são as ordenações aceitas na listagem dos cards de um set.
+var setFlashcardSortKeys = map[string]sortKey{
+	model.SortCardOrder: {expr: "f.card_order", cast: "int"},
+	model.SortCreatedAt: {expr: "f.created_at", cast: "timestamptz"},
+	model.SortUpdatedAt: {expr: "f.updated_at", cast: "timestamptz"},
+}
+
+// topicFlashcardSortKeys acrescenta topic, que só faz sentido na busca por tópico, que junta
+// cards de vários sets.
+var topicFlashcardSortKeys = map[string]sortKey{
+	model.SortCardOrder: setFlashcardSortKeys[model.SortCardOrder],
+	model.SortCreatedAt: setFlashcardSortKeys[model.SortCreatedAt],
+	model.SortUpdatedAt: setFlashcardSortKeys[model.SortUpdatedAt],
+	model.SortTopic:     {expr: "fs.topic", cast: "text"},
+}
+
+// ListBySetID devolve uma página dos cards do set e o cursor da próxima ("" na última).
+// Para carregar o set inteiro use GetAllBySetID.
+func (r *flashcardRepo) ListBySetID(ctx context.Context, setID uuid.UUID, page model.PageRequest) ([]model.Flashcard, string, error) {
+	ks, err := buildKeyset(page, setFlashcardSortKeys, "f.id", 2)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT f.id, f.flashcard_set_id, f.card_order, f.question_text, f.answer_text, f.source_timestamp, f.created_at, f.updated_at, ` + ks.sortExpr + `
+              FROM flashcards f
+              WHERE f.flashcard_set_id = $1 AND ` + ks.where + `
+              ` + ks.orderBy + ` ` + ks.limit
+
+	return r.queryPage(ctx, query, append([]any{setID}, ks.args...), page)
+}
+
+func (r *flashcardRepo) GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string, page model.PageRequest) ([]model.Flashcard, string, error) {
+	ks, err := buildKeyset(page, topicFlashcardSortKeys, "f.id", 3)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `
+		SELECT f.id, f.flashcard_set_id, f.card_order, f.question_text, f.answer_text, f.source_timestamp, f.created_at, f.updated_at, ` + ks.sortExpr + `
 		FROM flashcards f
 		JOIN flashcard_sets fs ON f.flashcard_set_id = fs.id
-		WHERE fs.user_id = $1 AND fs.topic ILIKE $2
-		ORDER BY f.card_order
-	`, userID, topic)
+		WHERE fs.user_id = $1 AND fs.topic ILIKE $2 AND ` + ks.where + `
+		` + ks.orderBy + ` ` + ks.limit
+
+	return r.queryPage(ctx, query, append([]any{userID, topic}, ks.args...), page)
+}
+
+// queryPage executa uma listagem montada com buildKeyset (cards seguidos do valor de sort).
+func (r *flashcardRepo) queryPage(ctx context.Context, query string, args []any, page model.PageRequest) ([]model.Flashcard, string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var flashcards []model.Flashcard
+	var sortValues []string
 	for rows.Next() {
 		var f model.Flashcard
+		var sortValue string
 		
-		err := rows.Scan(&f.ID, &f.FlashcardSetID, &f.CardOrder, &f.QuestionText, &f.AnswerText, &f.CreatedAt, &f.UpdatedAt)
+		err := rows.Scan(&f.ID, &f.FlashcardSetID, &f.CardOrder, &f.QuestionText, &f.AnswerText, &f.SourceTimestamp, &f.CreatedAt, &f.UpdatedAt, &sortValue)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		
 		flashcards = append(flashcards, f)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
-	return flashcards, nil
+
+	flashcards, next := trimPage(flashcards, sortValues, func(f model.Flashcard) uuid.UUID { return f.ID }, page)
+	return flashcards, next, nil
 }
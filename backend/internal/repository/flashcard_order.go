@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrCardsMismatch indica que os IDs informados não correspondem aos cards do set
+// (card de outro set, ID repetido ou, na reordenação, card faltando).
+var ErrCardsMismatch = errors.New("flashcard ids do not match the cards of the set")
+
+// As operações abaixo mudam card_order e rodam em transação, com os sets envolvidos
+// travados (FOR UPDATE) para que card_order continue denso (1..N) e sem repetições
+// mesmo com requisições concorrentes no mesmo set. O updated_at dos sets alterados é renovado
+// na mesma transação.
+
+// Append insere o card na última posição do set.
+func (r *flashcardRepo) Append(ctx context.Context, fc *model.Flashcard) error {
+	return r.inTx(ctx, func(ctx context.Context, tx querier) error {
+		if err := lockSets(ctx, tx, fc.FlashcardSetID); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO flashcards (flashcard_set_id, card_order, question_text, answer_text, created_at, updated_at)
+                  SELECT $1, COALESCE(MAX(card_order), 0) + 1, $2, $3, NOW(), NOW()
+                  FROM flashcards WHERE flashcard_set_id = $1
+                  RETURNING id, card_order, created_at, updated_at`
+
+		if err := tx.QueryRowContext(ctx, query, fc.FlashcardSetID, fc.QuestionText, fc.AnswerText).
+			Scan(&fc.ID, &fc.CardOrder, &fc.CreatedAt, &fc.UpdatedAt); err != nil {
+			return err
+		}
+		return touchSets(ctx, tx, fc.FlashcardSetID)
+	})
+}
+
+// Delete remove o card e fecha o buraco deixado em card_order.
+func (r *flashcardRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.inTx(ctx, func(ctx context.Context, tx querier) error {
+		var setID uuid.UUID
+		if err := tx.QueryRowContext(ctx, `SELECT flashcard_set_id FROM flashcards WHERE id = $1`, id).Scan(&setID); err != nil {
+			return err
+		}
+		if err := lockSets(ctx, tx, setID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM flashcards WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		if err := renumber(ctx, tx, setID); err != nil {
+			return err
+		}
+		return touchSets(ctx, tx, setID)
+	})
+}
+
+// Reorder aplica a nova ordem do set; orderedIDs precisa conter exatamente os cards do set.
+func (r *flashcardRepo) Reorder(ctx context.Context, setID uuid.UUID, orderedIDs []uuid.UUID) error {
+	return r.inTx(ctx, func(ctx context.Context, tx querier) error {
+		if err := lockSets(ctx, tx, setID); err != nil {
+			return err
+		}
+
+		var total int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM flashcards WHERE flashcard_set_id = $1`, setID).Scan(&total); err != nil {
+			return err
+		}
+		if total != len(orderedIDs) {
+			return ErrCardsMismatch
+		}
+
+		query := `UPDATE flashcards f SET card_order = o.ord, updated_at = NOW()
+                  FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ord)
+                  WHERE f.id = o.id AND f.flashcard_set_id = $1`
+
+		result, err := tx.ExecContext(ctx, query, setID, pq.Array(uuidStrings(orderedIDs)))
+		if err != nil {
+			return err
+		}
+		// IDs repetidos ou de outro set atualizam menos linhas que o total do set
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if int(n) != total {
+			return ErrCardsMismatch
+		}
+		return touchSets(ctx, tx, setID)
+	})
+}
+
+// Move transfere os cards para o fim do set de destino, mantendo a ordem relativa entre eles,
+// e compacta card_order no set de origem.
+func (r *flashcardRepo) Move(ctx context.Context, sourceSetID uuid.UUID, targetSetID uuid.UUID, ids []uuid.UUID) error {
+	return r.inTx(ctx, func(ctx context.Context, tx querier) error {
+		if err := lockSets(ctx, tx, sourceSetID, targetSetID); err != nil {
+			return err
+		}
+
+		query := `UPDATE flashcards f SET flashcard_set_id = $2, card_order = m.new_order, updated_at = NOW()
+                  FROM (
+                      SELECT id, (SELECT COALESCE(MAX(card_order), 0) FROM flashcards WHERE flashcard_set_id = $2)
+                                 + ROW_NUMBER() OVER (ORDER BY card_order) AS new_order
+                      FROM flashcards
+                      WHERE flashcard_set_id = $1 AND id = ANY($3::uuid[])
+                  ) m
+                  WHERE f.id = m.id`
+
+		result, err := tx.ExecContext(ctx, query, sourceSetID, targetSetID, pq.Array(uuidStrings(ids)))
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if int(n) != len(ids) {
+			return ErrCardsMismatch
+		}
+
+		if err := renumber(ctx, tx, sourceSetID); err != nil {
+			return err
+		}
+		return touchSets(ctx, tx, sourceSetID, targetSetID)
+	})
+}
+
+// Copy duplica os cards (com IDs novos) no fim do set de destino, mantendo a ordem relativa entre eles.
+func (r *flashcardRepo) Copy(ctx context.Context, sourceSetID uuid.UUID, targetSetID uuid.UUID, ids []uuid.UUID) ([]model.Flashcard, error) {
+	var copies []model.Flashcard
+	err := r.inTx(ctx, func(ctx context.Context, tx querier) error {
+		if err := lockSets(ctx, tx, sourceSetID, targetSetID); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO flashcards (flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at)
+                  SELECT $2, (SELECT COALESCE(MAX(card_order), 0) FROM flashcards WHERE flashcard_set_id = $2)
+                             + ROW_NUMBER() OVER (ORDER BY card_order),
+                         question_text, answer_text, source_timestamp, NOW(), NOW()
+                  FROM flashcards
+                  WHERE flashcard_set_id = $1 AND id = ANY($3::uuid[])
+                  RETURNING id, flashcard_set_id, card_order, question_text, answer_text, source_timestamp, created_at, updated_at`
+
+		rows, err := tx.QueryContext(ctx, query, sourceSetID, targetSetID, pq.Array(uuidStrings(ids)))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var fc model.Flashcard
+			if err := rows.Scan(&fc.ID, &fc.FlashcardSetID, &fc.CardOrder, &fc.QuestionText, &fc.AnswerText, &fc.SourceTimestamp, &fc.CreatedAt, &fc.UpdatedAt); err != nil {
+				return err
+			}
+			copies = append(copies, fc)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(copies) != len(ids) {
+			return ErrCardsMismatch
+		}
+
+		return touchSets(ctx, tx, targetSetID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(copies, func(i, j int) bool { return copies[i].CardOrder < copies[j].CardOrder })
+	return copies, nil
+}
+
+// inTx executa fn na transação do contexto ou, se não houver, em uma transação própria.
+func (r *flashcardRepo) inTx(ctx context.Context, fn func(ctx context.Context, tx querier) error) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		return fn(ctx, conn(ctx, r.db))
+	})
+}
+
+// lockSets trava as linhas dos sets, sempre na mesma ordem (por id) para evitar deadlock entre
+// uma movimentação A→B e outra B→A. Retorna sql.ErrNoRows se algum set não existir.
+func lockSets(ctx context.Context, tx querier, setIDs ...uuid.UUID) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM flashcard_sets WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`,
+		pq.Array(uuidStrings(setIDs)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	locked := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		locked[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range setIDs {
+		if !locked[id] {
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+// renumber reescreve card_order do set como 1..N, mantendo a ordem atual.
+func renumber(ctx context.Context, tx querier, setID uuid.UUID) error {
+	query := `UPDATE flashcards f SET card_order = r.rn, updated_at = NOW()
+              FROM (
+                  SELECT id, ROW_NUMBER() OVER (ORDER BY card_order, created_at, id) AS rn
+                  FROM flashcards
+                  WHERE flashcard_set_id = $1
+              ) r
+              WHERE f.id = r.id AND f.card_order <> r.rn`
+
+	_, err := tx.ExecContext(ctx, query, setID)
+	return err
+}
+
+func touchSets(ctx context.Context, tx querier, setIDs ...uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `UPDATE flashcard_sets SET updated_at = NOW() WHERE id = ANY($1::uuid[])`,
+		pq.Array(uuidStrings(setIDs)))
+	return err
+}
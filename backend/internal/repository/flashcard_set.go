@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/google/uuid"
@@ -11,7 +12,10 @@ import (
 type FlashcardSetRepository interface {
     Create(ctx context.Context, fc *model.FlashcardSet) (uuid.UUID, error)
     GetByID(ctx context.Context, setID uuid.UUID) (model.FlashcardSet, error)
-	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSet, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSet, string, error)
+	GetSummariesByUserID(ctx context.Context, userID uuid.UUID, now time.Time, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetSummary, string, error)
+	Update(ctx context.Context, set *model.FlashcardSet) error
+	Delete(ctx context.Context, setID uuid.UUID) error
 }
 
 type flashcardSetRepo struct {
@@ -23,11 +27,11 @@ func NewFlashcardSetRepository(db *sql.DB) FlashcardSetRepository {
 }
 
 func (r *flashcardSetRepo) Create(ctx context.Context, fcSet *model.FlashcardSet) (uuid.UUID, error) {
-    query := `INSERT INTO flashcard_sets (user_id, topic, created_at, updated_at)
-              VALUES ($1, $2, NOW(), NOW()) RETURNING id`
+    query := `INSERT INTO flashcard_sets (user_id, topic, difficulty, created_at, updated_at)
+              VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id`
     
     var newID uuid.UUID
-    err := r.db.QueryRowContext(ctx, query, fcSet.UserID, fcSet.Topic).
+    err := conn(ctx, r.db).QueryRowContext(ctx, query, fcSet.UserID, fcSet.Topic, fcSet.Difficulty).
         Scan(&newID)
     
     if err != nil {
@@ -39,34 +43,125 @@ func (r *flashcardSetRepo) Create(ctx context.Context, fcSet *model.FlashcardSet
 }
 
 func (r *flashcardSetRepo) GetByID(ctx context.Context, setID uuid.UUID) (model.FlashcardSet, error) {
-    query := `SELECT id, user_id, topic, created_at, updated_at FROM flashcard_sets WHERE id = $1`
+    query := `SELECT id, user_id, topic, difficulty, created_at, updated_at FROM flashcard_sets WHERE id = $1`
     var set model.FlashcardSet
     
-	err := r.db.QueryRowContext(ctx, query, setID).
-		Scan(&set.ID, &set.UserID, &set.Topic, &set.CreatedAt, &set.UpdatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, setID).
+		Scan(&set.ID, &set.UserID, &set.Topic, &set.Difficulty, &set.CreatedAt, &set.UpdatedAt)
     
     return set, err
 }
 
-func (r *flashcardSetRepo) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSet, error) {
-	query := `SELECT id, user_id, topic, created_at, updated_at FROM flashcard_sets WHERE user_id = $1 ORDER BY created_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+// setSortKeys são as ordenações aceitas nas listagens de sets.
+var setSortKeys = map[string]sortKey{
+	model.SortCreatedAt: {expr: "s.created_at", cast: "timestamptz"},
+	model.SortUpdatedAt: {expr: "s.updated_at", cast: "timestamptz"},
+	model.SortTopic:     {expr: "s.topic", cast: "text"},
+}
+
+// GetAllByUserID devolve uma página dos sets do usuário que passam no filtro e o cursor da próxima ("" na última).
+func (r *flashcardSetRepo) GetAllByUserID(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSet, string, error) {
+	ks, err := buildKeyset(page, setSortKeys, "s.id", 3)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	query := `SELECT s.id, s.user_id, s.topic, s.difficulty, s.created_at, s.updated_at, ` + ks.sortExpr + `
+              FROM flashcard_sets s
+              WHERE s.user_id = $1 AND ($2::text IS NULL OR s.difficulty = $2) AND ` + ks.where + `
+              ` + ks.orderBy + ` ` + ks.limit
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append([]any{userID, filter.Difficulty}, ks.args...)...)
+	if err != nil {
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var sets []model.FlashcardSet
+	var sortValues []string
 	for rows.Next() {
 		var set model.FlashcardSet
+		var sortValue string
 		
-		if err := rows.Scan(&set.ID, &set.UserID, &set.Topic, &set.CreatedAt, &set.UpdatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&set.ID, &set.UserID, &set.Topic, &set.Difficulty, &set.CreatedAt, &set.UpdatedAt, &sortValue); err != nil {
+			return nil, "", err
 		}
 		
 		sets = append(sets, set)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	sets, next := trimPage(sets, sortValues, func(s model.FlashcardSet) uuid.UUID { return s.ID }, page)
+	return sets, next, nil
+}
+
+// GetSummariesByUserID devolve uma página dos sets do usuário que passam no filtro, com total de cards,
+// cards vencidos até now, cards nunca estudados e a última revisão, tudo numa única query agregada.
+func (r *flashcardSetRepo) GetSummariesByUserID(ctx context.Context, userID uuid.UUID, now time.Time, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetSummary, string, error) {
+	ks, err := buildKeyset(page, setSortKeys, "s.id", 4)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT s.id, s.user_id, s.topic, s.difficulty, s.created_at, s.updated_at,
+                     COUNT(f.id),
+                     COUNT(cr.flashcard_id) FILTER (WHERE cr.due_at <= $2),
+                     COUNT(f.id) FILTER (WHERE cr.flashcard_id IS NULL),
+                     MAX(cr.last_reviewed_at),
+                     ` + ks.sortExpr + `
+              FROM flashcard_sets s
+              LEFT JOIN flashcards f ON f.flashcard_set_id = s.id
+              LEFT JOIN card_reviews cr ON cr.flashcard_id = f.id AND cr.user_id = s.user_id
+              WHERE s.user_id = $1 AND ($3::text IS NULL OR s.difficulty = $3) AND ` + ks.where + `
+              GROUP BY s.id
+              ` + ks.orderBy + ` ` + ks.limit
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append([]any{userID, now, filter.Difficulty}, ks.args...)...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var summaries []model.FlashcardSetSummary
+	var sortValues []string
+	for rows.Next() {
+		var sum model.FlashcardSetSummary
+		var lastStudied sql.NullTime
+		var sortValue string
+		if err := rows.Scan(&sum.ID, &sum.UserID, &sum.Topic, &sum.Difficulty, &sum.CreatedAt, &sum.UpdatedAt,
+			&sum.FlashcardCount, &sum.DueCount, &sum.NewCount, &lastStudied, &sortValue); err != nil {
+			return nil, "", err
+		}
+		if lastStudied.Valid {
+			sum.LastStudiedAt = &lastStudied.Time
+		}
+		summaries = append(summaries, sum)
+		sortValues = append(sortValues, sortValue)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	summaries, next := trimPage(summaries, sortValues, func(s model.FlashcardSetSummary) uuid.UUID { return s.ID }, page)
+	return summaries, next, nil
+}
+
+// Update grava o tópico e o nível do set e renova o updated_at.
+func (r *flashcardSetRepo) Update(ctx context.Context, set *model.FlashcardSet) error {
+	query := `UPDATE flashcard_sets SET topic = $2, difficulty = $3, updated_at = NOW()
+              WHERE id = $1
+              RETURNING updated_at`
 
-	return sets, nil
+	return conn(ctx, r.db).QueryRowContext(ctx, query, set.ID, set.Topic, set.Difficulty).Scan(&set.UpdatedAt)
 }
 
+// Delete remove o set; os cards são removidos em cascata pelo banco.
+func (r *flashcardSetRepo) Delete(ctx context.Context, setID uuid.UUID) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM flashcard_sets WHERE id = $1`, setID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job *model.GenerationJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (model.GenerationJob, error)
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (model.GenerationJob, error)
+	Heartbeat(ctx context.Context, id uuid.UUID, progress int) (string, error)
+	Complete(ctx context.Context, id uuid.UUID, setID uuid.UUID) error
+	Fail(ctx context.Context, id uuid.UUID, message string) error
+	Requeue(ctx context.Context, id uuid.UUID) error
+	Cancel(ctx context.Context, id uuid.UUID) (model.GenerationJob, error)
+}
+
+type jobRepo struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepo{db: db}
+}
+
+const jobColumns = `id, user_id, kind, payload, status, progress, flashcard_set_id, error, attempts,
+                    started_at, finished_at, created_at, updated_at`
+
+func (r *jobRepo) Create(ctx context.Context, job *model.GenerationJob) error {
+	query := `INSERT INTO generation_jobs (user_id, kind, payload, status, created_at, updated_at)
+              VALUES ($1, $2, $3, 'queued', NOW(), NOW())
+              RETURNING ` + jobColumns
+
+	return scanJob(conn(ctx, r.db).QueryRowContext(ctx, query, job.UserID, job.Kind, []byte(job.Payload)), job)
+}
+
+func (r *jobRepo) GetByID(ctx context.Context, id uuid.UUID) (model.GenerationJob, error) {
+	var job model.GenerationJob
+	err := scanJob(conn(ctx, r.db).QueryRowContext(ctx, `SELECT `+jobColumns+` FROM generation_jobs WHERE id = $1`, id), &job)
+	return job, err
+}
+
+// ClaimNext pega o job mais antigo na fila e o marca como running. Jobs running sem heartbeat
+// há mais de staleAfter (worker que caiu) também podem ser pegos de novo. SKIP LOCKED deixa
+// vários workers, inclusive em instâncias diferentes, consumirem a fila sem disputar a mesma linha.
+// Retorna sql.ErrNoRows se não houver nada para fazer.
+func (r *jobRepo) ClaimNext(ctx context.Context, staleAfter time.Duration) (model.GenerationJob, error) {
+	query := `UPDATE generation_jobs SET
+                  status = 'running',
+                  attempts = attempts + 1,
+                  started_at = COALESCE(started_at, NOW()),
+                  heartbeat_at = NOW(),
+                  updated_at = NOW()
+              WHERE id = (
+                  SELECT id FROM generation_jobs
+                  WHERE status = 'queued'
+                     OR (status = 'running' AND heartbeat_at < NOW() - make_interval(secs => $1))
+                  ORDER BY created_at
+                  FOR UPDATE SKIP LOCKED
+                  LIMIT 1
+              )
+              RETURNING ` + jobColumns
+
+	var job model.GenerationJob
+	err := scanJob(conn(ctx, r.db).QueryRowContext(ctx, query, staleAfter.Seconds()), &job)
+	return job, err
+}
+
+// Heartbeat renova o heartbeat, grava o progresso e devolve o status atual, para o worker
+// perceber quando o job foi cancelado.
+func (r *jobRepo) Heartbeat(ctx context.Context, id uuid.UUID, progress int) (string, error) {
+	query := `UPDATE generation_jobs SET
+                  heartbeat_at = NOW(),
+                  progress = CASE WHEN status = 'running' THEN GREATEST(progress, $2) ELSE progress END,
+                  updated_at = NOW()
+              WHERE id = $1
+              RETURNING status`
+
+	var status string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, progress).Scan(&status)
+	return status, err
+}
+
+// Complete marca o job como concluído com o set gerado. Retorna sql.ErrNoRows se o job
+// não está mais rodando (por exemplo, foi cancelado), para o chamador desfazer a transação.
+func (r *jobRepo) Complete(ctx context.Context, id uuid.UUID, setID uuid.UUID) error {
+	query := `UPDATE generation_jobs SET
+                  status = 'succeeded', progress = 100, flashcard_set_id = $2, error = NULL,
+                  finished_at = NOW(), updated_at = NOW()
+              WHERE id = $1 AND status = 'running'`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, setID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *jobRepo) Fail(ctx context.Context, id uuid.UUID, message string) error {
+	query := `UPDATE generation_jobs SET
+                  status = 'failed', error = $2, finished_at = NOW(), updated_at = NOW()
+              WHERE id = $1 AND status = 'running'`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, message)
+	return err
+}
+
+// Requeue devolve um job interrompido (desligamento do servidor) para a fila. A interrupção não é
+// culpa do job, então a tentativa contada em ClaimNext é devolvida.
+func (r *jobRepo) Requeue(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE generation_jobs SET
+                  status = 'queued', progress = 0, heartbeat_at = NULL,
+                  attempts = GREATEST(attempts - 1, 0), updated_at = NOW()
+              WHERE id = $1 AND status = 'running'`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// Cancel cancela um job que ainda não terminou. Retorna sql.ErrNoRows se ele não existe
+// ou já está num estado final.
+func (r *jobRepo) Cancel(ctx context.Context, id uuid.UUID) (model.GenerationJob, error) {
+	query := `UPDATE generation_jobs SET
+                  status = 'cancelled', finished_at = NOW(), updated_at = NOW()
+              WHERE id = $1 AND status IN ('queued', 'running')
+              RETURNING ` + jobColumns
+
+	var job model.GenerationJob
+	err := scanJob(conn(ctx, r.db).QueryRowContext(ctx, query, id), &job)
+	return job, err
+}
+
+func scanJob(row *sql.Row, job *model.GenerationJob) error {
+	var setID uuid.NullUUID
+	var errMsg sql.NullString
+	var startedAt, finishedAt sql.NullTime
+	var payload []byte
+
+	err := row.Scan(&job.ID, &job.UserID, &job.Kind, &payload, &job.Status, &job.Progress, &setID, &errMsg, &job.Attempts,
+		&startedAt, &finishedAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	job.Payload = payload
+	if setID.Valid {
+		job.FlashcardSetID = &setID.UUID
+	}
+	if errMsg.Valid {
+		job.Error = &errMsg.String
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// sortKey mapeia um valor de ?sort= para a expressão SQL ordenada e o tipo usado para
+// converter de volta o valor guardado no cursor (que trafega como texto).
+type sortKey struct {
+	expr string
+	cast string
+}
+
+// text devolve a expressão que vai para o cursor. Instantes saem em RFC 3339 (UTC, com
+// microssegundos), o formato que model.DecodeCursor confere e que o Postgres lê de volta.
+func (k sortKey) text() string {
+	if k.cast == "timestamptz" {
+		return fmt.Sprintf(`to_char((%s) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`, k.expr)
+	}
+	return fmt.Sprintf("(%s)::text", k.expr)
+}
+
+// keyset monta a paginação por cursor: a expressão de sort é selecionada como texto (sortExpr),
+// where filtra o que vem depois do cursor e orderBy/limit fecham a query. A página busca um item
+// a mais que o limite para saber se existe uma próxima.
+type keyset struct {
+	sortExpr string
+	where    string
+	orderBy  string
+	limit    string
+	args     []any
+}
+
+// buildKeyset gera os fragmentos para p. idExpr é a coluna de desempate e nextArg o número do
+// próximo placeholder livre na query.
+func buildKeyset(p model.PageRequest, keys map[string]sortKey, idExpr string, nextArg int) (keyset, error) {
+	key, ok := keys[p.Sort]
+	if !ok {
+		return keyset{}, fmt.Errorf("unsupported sort %q", p.Sort)
+	}
+
+	dir, cmp := "ASC", ">"
+	if p.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	ks := keyset{
+		sortExpr: key.text(),
+		orderBy:  fmt.Sprintf("ORDER BY %s %s, %s %s", key.expr, dir, idExpr, dir),
+		limit:    fmt.Sprintf("LIMIT %d", p.Limit+1),
+		where:    "TRUE",
+	}
+	if p.Cursor != nil {
+		ks.where = fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d::uuid)", key.expr, idExpr, cmp, nextArg, key.cast, nextArg+1)
+		ks.args = []any{p.Cursor.Value, p.Cursor.ID}
+	}
+	return ks, nil
+}
+
+// trimPage descarta o item extra buscado por buildKeyset e, se ele existia, devolve o cursor
+// apontando para o último item da página. sortValues[i] é o valor de sortExpr do item i.
+func trimPage[T any](items []T, sortValues []string, id func(T) uuid.UUID, p model.PageRequest) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+	items = items[:p.Limit]
+	last := len(items) - 1
+	next := model.Cursor{Sort: p.Sort, Desc: p.Desc, Value: sortValues[last], ID: id(items[last])}
+	return items, next.Encode()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestBuildKeysetFirstPage(t *testing.T) {
+	ks, err := buildKeyset(model.PageRequest{Limit: 10, Sort: model.SortCardOrder}, setFlashcardSortKeys, "f.id", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks.where != "TRUE" || len(ks.args) != 0 {
+		t.Errorf("first page should not filter: where = %q, args = %v", ks.where, ks.args)
+	}
+	if want := "ORDER BY f.card_order ASC, f.id ASC"; ks.orderBy != want {
+		t.Errorf("orderBy = %q, want %q", ks.orderBy, want)
+	}
+	if want := "LIMIT 11"; ks.limit != want {
+		t.Errorf("limit = %q, want %q (one extra to detect a next page)", ks.limit, want)
+	}
+}
+
+func TestBuildKeysetAfterCursor(t *testing.T) {
+	id := uuid.New()
+	page := model.PageRequest{
+		Limit:  5,
+		Sort:   model.SortCreatedAt,
+		Desc:   true,
+		Cursor: &model.Cursor{Sort: model.SortCreatedAt, Desc: true, Value: "2026-10-17T12:00:00Z", ID: id},
+	}
+	ks, err := buildKeyset(page, setFlashcardSortKeys, "f.id", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(f.created_at, f.id) < ($3::timestamptz, $4::uuid)"; ks.where != want {
+		t.Errorf("where = %q, want %q", ks.where, want)
+	}
+	if len(ks.args) != 2 || ks.args[0] != "2026-10-17T12:00:00Z" || ks.args[1] != id {
+		t.Errorf("args = %v", ks.args)
+	}
+}
+
+func TestBuildKeysetRejectsUnknownSort(t *testing.T) {
+	// topic só existe na busca por tópico, que junta cards de vários sets
+	if _, err := buildKeyset(model.PageRequest{Limit: 10, Sort: model.SortTopic}, setFlashcardSortKeys, "f.id", 2); err == nil {
+		t.Error("expected topic to be rejected for a single set listing")
+	}
+	if _, err := buildKeyset(model.PageRequest{Limit: 10, Sort: model.SortTopic}, topicFlashcardSortKeys, "f.id", 3); err != nil {
+		t.Errorf("topic listing: %v", err)
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	values := []string{"1", "2", "3"}
+	page := model.PageRequest{Limit: 2, Sort: model.SortCardOrder}
+	id := func(u uuid.UUID) uuid.UUID { return u }
+
+	items, next := trimPage(ids, values, id, page)
+	if len(items) != 2 || next == "" {
+		t.Fatalf("items = %d, next = %q; want 2 items and a cursor", len(items), next)
+	}
+	cursor, err := model.DecodeCursor(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.Cursor{Sort: model.SortCardOrder, Value: "2", ID: ids[1]}
+	if cursor != want {
+		t.Errorf("cursor = %+v, want %+v", cursor, want)
+	}
+
+	if items, next := trimPage(ids[:2], values[:2], id, page); len(items) != 2 || next != "" {
+		t.Errorf("last page: items = %d, next = %q; want 2 items and no cursor", len(items), next)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"empty", ""},
+		{"not base64", "not base64!"},
+		{"no id", model.Cursor{Sort: model.SortTopic}.Encode()},
+		{"bad time", model.Cursor{Sort: model.SortCreatedAt, Value: "ontem", ID: id}.Encode()},
+		{"postgres time format", model.Cursor{Sort: model.SortUpdatedAt, Value: "2026-10-17 12:00:00+00", ID: id}.Encode()},
+		{"bad card order", model.Cursor{Sort: model.SortCardOrder, Value: "1; DROP", ID: id}.Encode()},
+		{"card order out of range", model.Cursor{Sort: model.SortCardOrder, Value: "99999999999", ID: id}.Encode()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := model.DecodeCursor(tt.s); err != model.ErrInvalidCursor {
+				t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", tt.s, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorAcceptsSortValues(t *testing.T) {
+	id := uuid.New()
+	for _, c := range []model.Cursor{
+		{Sort: model.SortCreatedAt, Value: "2026-10-17T12:00:00.123456Z", ID: id},
+		{Sort: model.SortUpdatedAt, Desc: true, Value: "2026-10-17T12:00:00Z", ID: id},
+		{Sort: model.SortCardOrder, Value: "42", ID: id},
+		{Sort: model.SortTopic, Value: "Cardiologia: arritmias", ID: id},
+	} {
+		got, err := model.DecodeCursor(c.Encode())
+		if err != nil || got != c {
+			t.Errorf("DecodeCursor(%+v) = %+v, %v", c, got, err)
+		}
+	}
+}
+
+func TestSortExprMatchesCursorFormat(t *testing.T) {
+	ks, err := buildKeyset(model.PageRequest{Limit: 10, Sort: model.SortUpdatedAt}, setSortKeys, "s.id", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `to_char((s.updated_at) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`; ks.sortExpr != want {
+		t.Errorf("sortExpr = %q, want %q", ks.sortExpr, want)
+	}
+
+	ks, err = buildKeyset(model.PageRequest{Limit: 10, Sort: model.SortTopic}, setSortKeys, "s.id", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(s.topic)::text"; ks.sortExpr != want {
+		t.Errorf("sortExpr = %q, want %q", ks.sortExpr, want)
+	}
+}
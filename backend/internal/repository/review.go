@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+type ReviewRepository interface {
+	GetByFlashcard(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) (model.CardReview, error)
+	GetByFlashcardForUpdate(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) (model.CardReview, error)
+	Upsert(ctx context.Context, review *model.CardReview) error
+	CreateLog(ctx context.Context, log *model.ReviewLog) error
+	GetLogsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]model.ReviewLog, error)
+	GetDueCards(ctx context.Context, userID uuid.UUID, dueBefore time.Time, limit int) ([]model.DueCard, error)
+	GetNewCards(ctx context.Context, userID uuid.UUID, limit int) ([]model.DueCard, error)
+	CountStudiedSince(ctx context.Context, userID uuid.UUID, since time.Time) (newCards int, reviews int, err error)
+}
+
+type reviewRepo struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) ReviewRepository {
+	return &reviewRepo{db: db}
+}
+
+func (r *reviewRepo) GetByFlashcard(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) (model.CardReview, error) {
+	return r.getByFlashcard(ctx, userID, flashcardID, "")
+}
+
+// GetByFlashcardForUpdate lê o estado como GetByFlashcard e trava a linha até o fim da transação
+// do contexto, para que duas respostas ao mesmo card não partam do mesmo estado.
+func (r *reviewRepo) GetByFlashcardForUpdate(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) (model.CardReview, error) {
+	return r.getByFlashcard(ctx, userID, flashcardID, " FOR UPDATE")
+}
+
+func (r *reviewRepo) getByFlashcard(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID, lock string) (model.CardReview, error) {
+	query := `SELECT user_id, flashcard_id, ease_factor, interval_days, repetitions, stability, difficulty, due_at, last_reviewed_at, created_at, updated_at
+              FROM card_reviews
+              WHERE user_id = $1 AND flashcard_id = $2` + lock
+
+	var review model.CardReview
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, flashcardID).
+		Scan(&review.UserID, &review.FlashcardID, &review.EaseFactor, &review.IntervalDays, &review.Repetitions,
+			&review.Stability, &review.Difficulty, &review.DueAt, &review.LastReviewedAt, &review.CreatedAt, &review.UpdatedAt)
+
+	return review, err
+}
+
+// Upsert cria ou atualiza o estado de agendamento do card para o usuário.
+func (r *reviewRepo) Upsert(ctx context.Context, review *model.CardReview) error {
+	query := `INSERT INTO card_reviews (user_id, flashcard_id, ease_factor, interval_days, repetitions, stability, difficulty, due_at, last_reviewed_at, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+              ON CONFLICT (user_id, flashcard_id) DO UPDATE SET
+                  ease_factor = EXCLUDED.ease_factor,
+                  interval_days = EXCLUDED.interval_days,
+                  repetitions = EXCLUDED.repetitions,
+                  stability = EXCLUDED.stability,
+                  difficulty = EXCLUDED.difficulty,
+                  due_at = EXCLUDED.due_at,
+                  last_reviewed_at = EXCLUDED.last_reviewed_at,
+                  updated_at = NOW()
+              RETURNING created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, review.UserID, review.FlashcardID, review.EaseFactor, review.IntervalDays,
+		review.Repetitions, review.Stability, review.Difficulty, review.DueAt, review.LastReviewedAt).
+		Scan(&review.CreatedAt, &review.UpdatedAt)
+}
+
+// CreateLog registra uma resposta no histórico de revisões.
+func (r *reviewRepo) CreateLog(ctx context.Context, log *model.ReviewLog) error {
+	stateBefore, err := marshalState(log.StateBefore)
+	if err != nil {
+		return err
+	}
+	stateAfter, err := marshalState(log.StateAfter)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO review_logs (user_id, flashcard_id, session_id, grade, response_time_ms, scheduler, state_before, state_after, reviewed_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, log.UserID, log.FlashcardID, log.SessionID, log.Grade, log.ResponseTimeMs,
+		log.Scheduler, stateBefore, stateAfter, log.ReviewedAt).
+		Scan(&log.ID)
+}
+
+// GetLogsByUserID devolve, em ordem cronológica, o histórico completo dos cards estudados mais
+// recentemente pelo usuário, somando no máximo limit logs. Cards entram inteiros ou não entram,
+// para o histórico de cada um poder ser reproduzido desde a primeira revisão.
+func (r *reviewRepo) GetLogsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]model.ReviewLog, error) {
+	query := `WITH cards AS (
+                  SELECT flashcard_id,
+                         SUM(COUNT(*)) OVER (ORDER BY MAX(reviewed_at) DESC, flashcard_id) AS running
+                  FROM review_logs
+                  WHERE user_id = $1
+                  GROUP BY flashcard_id
+              )
+              SELECT id, user_id, flashcard_id, session_id, grade, response_time_ms, scheduler, state_before, state_after, reviewed_at
+              FROM review_logs
+              WHERE user_id = $1 AND flashcard_id IN (SELECT flashcard_id FROM cards WHERE running <= $2)
+              ORDER BY reviewed_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []model.ReviewLog
+	for rows.Next() {
+		var l model.ReviewLog
+		var stateBefore, stateAfter []byte
+		if err := rows.Scan(&l.ID, &l.UserID, &l.FlashcardID, &l.SessionID, &l.Grade, &l.ResponseTimeMs, &l.Scheduler,
+			&stateBefore, &stateAfter, &l.ReviewedAt); err != nil {
+			return nil, err
+		}
+		if l.StateBefore, err = unmarshalState(stateBefore); err != nil {
+			return nil, err
+		}
+		if l.StateAfter, err = unmarshalState(stateAfter); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
+// GetDueCards busca as revisões vencidas antes de dueBefore em todos os sets do usuário, da mais atrasada para a mais recente.
+func (r *reviewRepo) GetDueCards(ctx context.Context, userID uuid.UUID, dueBefore time.Time, limit int) ([]model.DueCard, error) {
+	query := `SELECT f.id, f.flashcard_set_id, f.card_order, f.question_text, f.answer_text, f.created_at, f.updated_at, fs.topic, cr.due_at
+              FROM card_reviews cr
+              JOIN flashcards f ON f.id = cr.flashcard_id
+              JOIN flashcard_sets fs ON fs.id = f.flashcard_set_id
+              WHERE cr.user_id = $1 AND fs.user_id = $1 AND cr.due_at < $2
+              ORDER BY cr.due_at, f.card_order
+              LIMIT $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dueBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []model.DueCard
+	for rows.Next() {
+		var card model.DueCard
+		if err := rows.Scan(&card.ID, &card.FlashcardSetID, &card.CardOrder, &card.QuestionText, &card.AnswerText,
+			&card.CreatedAt, &card.UpdatedAt, &card.Topic, &card.DueAt); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// GetNewCards busca cards que o usuário ainda não estudou, dos sets mais antigos para os mais novos.
+func (r *reviewRepo) GetNewCards(ctx context.Context, userID uuid.UUID, limit int) ([]model.DueCard, error) {
+	query := `SELECT f.id, f.flashcard_set_id, f.card_order, f.question_text, f.answer_text, f.created_at, f.updated_at, fs.topic
+              FROM flashcards f
+              JOIN flashcard_sets fs ON fs.id = f.flashcard_set_id
+              WHERE fs.user_id = $1
+                AND NOT EXISTS (SELECT 1 FROM card_reviews cr WHERE cr.user_id = $1 AND cr.flashcard_id = f.id)
+              ORDER BY fs.created_at, f.card_order
+              LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []model.DueCard
+	for rows.Next() {
+		card := model.DueCard{IsNew: true}
+		if err := rows.Scan(&card.ID, &card.FlashcardSetID, &card.CardOrder, &card.QuestionText, &card.AnswerText,
+			&card.CreatedAt, &card.UpdatedAt, &card.Topic); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// CountStudiedSince conta quantos cards novos e quantos cards em revisão o usuário já estudou desde since.
+// Um card conta como novo se a primeira revisão dele aconteceu depois de since.
+func (r *reviewRepo) CountStudiedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, int, error) {
+	query := `SELECT
+                  COUNT(DISTINCT flashcard_id) FILTER (WHERE first_reviewed_at >= $2),
+                  COUNT(DISTINCT flashcard_id) FILTER (WHERE first_reviewed_at < $2)
+              FROM (
+                  SELECT flashcard_id, reviewed_at, MIN(reviewed_at) OVER (PARTITION BY flashcard_id) AS first_reviewed_at
+                  FROM review_logs
+                  WHERE user_id = $1
+              ) l
+              WHERE reviewed_at >= $2`
+
+	var newCards, reviews int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, since).Scan(&newCards, &reviews)
+	return newCards, reviews, err
+}
+
+// marshalState converte o estado para JSONB; nil vira NULL.
+func marshalState(state *model.ScheduleState) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
+
+func unmarshalState(data []byte) (*model.ScheduleState, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var state model.ScheduleState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type SettingsRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (model.UserSettings, error)
+	Upsert(ctx context.Context, settings *model.UserSettings) error
+	ClaimOptimization(ctx context.Context, userID uuid.UUID, cooldown time.Duration) (bool, error)
+}
+
+type settingsRepo struct {
+	db *sql.DB
+}
+
+func NewSettingsRepository(db *sql.DB) SettingsRepository {
+	return &settingsRepo{db: db}
+}
+
+func (r *settingsRepo) GetByUserID(ctx context.Context, userID uuid.UUID) (model.UserSettings, error) {
+	query := `SELECT user_id, scheduler, desired_retention, fsrs_weights, new_cards_per_day, reviews_per_day, fsrs_optimized_at, created_at, updated_at
+              FROM user_settings
+              WHERE user_id = $1`
+
+	var settings model.UserSettings
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).
+		Scan(&settings.UserID, &settings.Scheduler, &settings.DesiredRetention, pq.Array(&settings.FSRSWeights),
+			&settings.NewCardsPerDay, &settings.ReviewsPerDay, &settings.FSRSOptimizedAt, &settings.CreatedAt, &settings.UpdatedAt)
+
+	return settings, err
+}
+
+func (r *settingsRepo) Upsert(ctx context.Context, settings *model.UserSettings) error {
+	query := `INSERT INTO user_settings (user_id, scheduler, desired_retention, fsrs_weights, new_cards_per_day, reviews_per_day, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+              ON CONFLICT (user_id) DO UPDATE SET
+                  scheduler = EXCLUDED.scheduler,
+                  desired_retention = EXCLUDED.desired_retention,
+                  fsrs_weights = EXCLUDED.fsrs_weights,
+                  new_cards_per_day = EXCLUDED.new_cards_per_day,
+                  reviews_per_day = EXCLUDED.reviews_per_day,
+                  updated_at = NOW()
+              RETURNING created_at, updated_at`
+
+	var w interface{}
+	if len(settings.FSRSWeights) > 0 {
+		w = pq.Array(settings.FSRSWeights)
+	}
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, settings.UserID, settings.Scheduler, settings.DesiredRetention, w,
+		settings.NewCardsPerDay, settings.ReviewsPerDay).
+		Scan(&settings.CreatedAt, &settings.UpdatedAt)
+}
+
+// ClaimOptimization marca agora como o momento da última otimização do FSRS, desde que a anterior
+// tenha sido há mais de cooldown. Devolve false (sem alterar nada) se ainda está no intervalo ou se
+// o usuário não tem linha em user_settings. A checagem e a marcação são um só UPDATE, então duas
+// requisições simultâneas não passam juntas.
+func (r *settingsRepo) ClaimOptimization(ctx context.Context, userID uuid.UUID, cooldown time.Duration) (bool, error) {
+	query := `UPDATE user_settings SET fsrs_optimized_at = NOW()
+              WHERE user_id = $1 AND (fsrs_optimized_at IS NULL OR fsrs_optimized_at <= NOW() - make_interval(secs => $2))`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, cooldown.Seconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+type SourceRepository interface {
+	Create(ctx context.Context, source *model.Source) error
+	GetByID(ctx context.Context, id uuid.UUID) (model.Source, error)
+}
+
+type sourceRepo struct {
+	db *sql.DB
+}
+
+func NewSourceRepository(db *sql.DB) SourceRepository {
+	return &sourceRepo{db: db}
+}
+
+const sourceColumns = `id, user_id, file_name, content_type, mime_type, size_bytes, storage_key, created_at`
+
+// Create grava os metadados de um arquivo já enviado ao blob store. O ID vem preenchido,
+// porque faz parte da chave do blob.
+func (r *sourceRepo) Create(ctx context.Context, source *model.Source) error {
+	query := `INSERT INTO sources (id, user_id, file_name, content_type, mime_type, size_bytes, storage_key, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+              RETURNING ` + sourceColumns
+
+	return scanSource(conn(ctx, r.db).QueryRowContext(ctx, query, source.ID, source.UserID, source.FileName,
+		source.ContentType, source.MIMEType, source.SizeBytes, source.StorageKey), source)
+}
+
+func (r *sourceRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Source, error) {
+	var source model.Source
+	err := scanSource(conn(ctx, r.db).QueryRowContext(ctx, `SELECT `+sourceColumns+` FROM sources WHERE id = $1`, id), &source)
+	return source, err
+}
+
+func scanSource(row *sql.Row, source *model.Source) error {
+	return row.Scan(&source.ID, &source.UserID, &source.FileName, &source.ContentType, &source.MIMEType,
+		&source.SizeBytes, &source.StorageKey, &source.CreatedAt)
+}
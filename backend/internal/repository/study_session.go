@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type StudySessionRepository interface {
+	Create(ctx context.Context, session *model.StudySession) error
+	GetByID(ctx context.Context, sessionID uuid.UUID) (model.StudySession, error)
+	Finish(ctx context.Context, sessionID uuid.UUID) (model.StudySession, error)
+}
+
+type studySessionRepo struct {
+	db *sql.DB
+}
+
+func NewStudySessionRepository(db *sql.DB) StudySessionRepository {
+	return &studySessionRepo{db: db}
+}
+
+func (r *studySessionRepo) Create(ctx context.Context, session *model.StudySession) error {
+	query := `INSERT INTO study_sessions (user_id, set_ids, started_at, created_at, updated_at)
+              VALUES ($1, $2, NOW(), NOW(), NOW())
+              RETURNING id, started_at, created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, session.UserID, pq.Array(uuidStrings(session.SetIDs))).
+		Scan(&session.ID, &session.StartedAt, &session.CreatedAt, &session.UpdatedAt)
+}
+
+func (r *studySessionRepo) GetByID(ctx context.Context, sessionID uuid.UUID) (model.StudySession, error) {
+	query := `SELECT id, user_id, set_ids, started_at, ended_at, answers_count, correct_count, average_response_ms, created_at, updated_at
+              FROM study_sessions
+              WHERE id = $1`
+
+	return scanStudySession(conn(ctx, r.db).QueryRowContext(ctx, query, sessionID))
+}
+
+// Finish encerra a sessão e grava o resumo calculado a partir das respostas em review_logs.
+// Retorna sql.ErrNoRows se a sessão não existe ou já foi encerrada.
+func (r *studySessionRepo) Finish(ctx context.Context, sessionID uuid.UUID) (model.StudySession, error) {
+	query := `UPDATE study_sessions s SET
+                  ended_at = NOW(),
+                  answers_count = a.answers,
+                  correct_count = a.correct,
+                  average_response_ms = a.avg_response_ms,
+                  updated_at = NOW()
+              FROM (
+                  SELECT COUNT(*) AS answers,
+                         COUNT(*) FILTER (WHERE grade >= 3) AS correct,
+                         ROUND(AVG(response_time_ms))::INTEGER AS avg_response_ms
+                  FROM review_logs
+                  WHERE session_id = $1
+              ) a
+              WHERE s.id = $1 AND s.ended_at IS NULL
+              RETURNING s.id, s.user_id, s.set_ids, s.started_at, s.ended_at, s.answers_count, s.correct_count,
+                        s.average_response_ms, s.created_at, s.updated_at`
+
+	return scanStudySession(conn(ctx, r.db).QueryRowContext(ctx, query, sessionID))
+}
+
+func scanStudySession(row *sql.Row) (model.StudySession, error) {
+	var session model.StudySession
+	var setIDs []string
+	err := row.Scan(&session.ID, &session.UserID, pq.Array(&setIDs), &session.StartedAt, &session.EndedAt,
+		&session.AnswersCount, &session.CorrectCount, &session.AverageResponseMs, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return model.StudySession{}, err
+	}
+
+	session.SetIDs, err = parseUUIDs(setIDs)
+	return session, err
+}
+
+// uuidStrings e parseUUIDs convertem entre []uuid.UUID e o formato aceito por pq.Array para colunas UUID[].
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(values))
+	for i, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Transactor abre uma transação e a coloca no contexto. Todo repositório que recebe esse
+// contexto executa suas queries dentro dela, então várias chamadas a repositórios diferentes
+// fazem commit ou rollback juntas.
+type Transactor interface {
+	// WithinTransaction executa fn em uma transação: commit se fn retornar nil, rollback caso
+	// contrário (ou em panic). Se o contexto já carregar uma transação, fn participa dela.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor cria um Transactor sobre a mesma conexão usada pelos repositórios.
+func NewTransactor(db *sql.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, t.db, fn)
+}
+
+type txKey struct{}
+
+// querier é o subconjunto comum de *sql.DB e *sql.Tx usado pelos repositórios.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn devolve a transação do contexto, se houver, ou a conexão do pool.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+func withinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
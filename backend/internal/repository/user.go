@@ -23,17 +23,17 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepo) GetByID(ctx context.Context, userID uuid.UUID) (model.User, error) {
-	query := `SELECT id, email, password_hash, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, email, password_hash, plan, created_at, updated_at FROM users WHERE id = $1`
 	var user model.User
-	err := r.db.QueryRowContext(ctx, query, userID).
-		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Plan, &user.CreatedAt, &user.UpdatedAt)
 	return user, err
 }
 
 func (r *userRepo) Create(ctx context.Context, user *model.User) error {
 	query := `INSERT INTO users (id, email, password_hash, created_at, updated_at)
               VALUES ($1, $2, $3, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Email, user.PasswordHash)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.ID, user.Email, user.PasswordHash)
 	return err
 }
 
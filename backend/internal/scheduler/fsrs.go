@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// Rating é a nota na escala de 4 botões usada pelo FSRS.
+type Rating int
+
+const (
+	Again Rating = iota + 1
+	Hard
+	Good
+	Easy
+)
+
+// RatingFromGrade converte a nota SM-2 (0 a 5) para a escala do FSRS:
+// 0-2 esqueceu, 3 difícil, 4 bom, 5 fácil.
+func RatingFromGrade(grade int) Rating {
+	switch {
+	case grade < PassingGrade:
+		return Again
+	case grade == 3:
+		return Hard
+	case grade == 4:
+		return Good
+	default:
+		return Easy
+	}
+}
+
+const (
+	// NumWeights é a quantidade de parâmetros do FSRS-4.5.
+	NumWeights = 17
+	// DefaultDesiredRetention é a probabilidade de lembrar o card no dia da revisão.
+	DefaultDesiredRetention = 0.9
+
+	decay           = -0.5
+	factor          = 19.0 / 81.0
+	minStability    = 0.1
+	maxIntervalDays = 36500
+)
+
+// DefaultWeights são os pesos padrão publicados do FSRS-4.5, usados até o usuário otimizar os seus.
+var DefaultWeights = []float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+type fsrs struct {
+	w         weights
+	retention float64
+}
+
+// NewFSRS cria o agendador FSRS com os pesos e a retenção desejada do usuário.
+// Pesos vazios ou retenção zero usam os valores padrão.
+func NewFSRS(w []float64, retention float64) (Scheduler, error) {
+	if len(w) == 0 {
+		w = DefaultWeights
+	}
+	if len(w) != NumWeights {
+		return nil, fmt.Errorf("fsrs expects %d weights, got %d", NumWeights, len(w))
+	}
+	if retention == 0 {
+		retention = DefaultDesiredRetention
+	}
+	if retention <= 0 || retention >= 1 {
+		return nil, fmt.Errorf("desired retention must be between 0 and 1, got %v", retention)
+	}
+	return &fsrs{w: append(weights(nil), w...), retention: retention}, nil
+}
+
+func (f *fsrs) Name() string {
+	return FSRS
+}
+
+// Schedule atualiza estabilidade e dificuldade do card e agenda a próxima revisão
+// para o dia em que a retrievability cai para a retenção desejada.
+func (f *fsrs) Schedule(review *model.CardReview, grade int, now time.Time) {
+	rating := RatingFromGrade(grade)
+
+	s, d := review.Stability, review.Difficulty
+	elapsed := 0.0
+	if review.LastReviewedAt != nil {
+		elapsed = math.Max(now.Sub(*review.LastReviewedAt).Hours()/24, 0)
+		if s == 0 {
+			// Card já estudado com SM-2: o intervalo atual serve de estimativa da estabilidade
+			s = math.Max(float64(review.IntervalDays), minStability)
+			d = f.w.initDifficulty(Good)
+		}
+	}
+	review.Stability, review.Difficulty = f.w.step(s, d, elapsed, rating)
+
+	if rating == Again {
+		review.Repetitions = 0
+	} else {
+		review.Repetitions++
+	}
+	review.IntervalDays = f.nextInterval(review.Stability)
+	review.LastReviewedAt = &now
+	review.DueAt = now.AddDate(0, 0, review.IntervalDays)
+}
+
+func (f *fsrs) nextInterval(stability float64) int {
+	interval := math.Round(stability / factor * (math.Pow(f.retention, 1/decay) - 1))
+	return int(math.Min(math.Max(interval, 1), maxIntervalDays))
+}
+
+// weights agrupa as fórmulas do FSRS-4.5 para um conjunto de parâmetros,
+// compartilhadas entre o agendador e o otimizador.
+type weights []float64
+
+// step devolve estabilidade e dificuldade após uma revisão; s == 0 indica a primeira revisão do card.
+func (w weights) step(s, d, elapsedDays float64, rating Rating) (float64, float64) {
+	if s == 0 {
+		return w.initStability(rating), w.initDifficulty(rating)
+	}
+
+	r := retrievability(elapsedDays, s)
+	var next float64
+	if rating == Again {
+		next = math.Min(w.forgetStability(d, s, r), s)
+	} else {
+		next = w.recallStability(d, s, r, rating)
+	}
+	return math.Max(next, minStability), w.nextDifficulty(d, rating)
+}
+
+func (w weights) initStability(rating Rating) float64 {
+	return math.Max(w[rating-1], minStability)
+}
+
+func (w weights) initDifficulty(rating Rating) float64 {
+	return clamp(w[4]-float64(rating-3)*w[5], 1, 10)
+}
+
+func (w weights) nextDifficulty(d float64, rating Rating) float64 {
+	next := d - w[6]*float64(rating-3)
+	// Reversão à média para a dificuldade não ficar presa nos extremos
+	return clamp(w[7]*w.initDifficulty(Easy)+(1-w[7])*next, 1, 10)
+}
+
+func (w weights) recallStability(d, s, r float64, rating Rating) float64 {
+	hardPenalty, easyBonus := 1.0, 1.0
+	if rating == Hard {
+		hardPenalty = w[15]
+	}
+	if rating == Easy {
+		easyBonus = w[16]
+	}
+	return s * (1 + math.Exp(w[8])*(11-d)*math.Pow(s, -w[9])*(math.Exp((1-r)*w[10])-1)*hardPenalty*easyBonus)
+}
+
+func (w weights) forgetStability(d, s, r float64) float64 {
+	return w[11] * math.Pow(d, -w[12]) * (math.Pow(s+1, w[13]) - 1) * math.Exp((1-r)*w[14])
+}
+
+// retrievability é a probabilidade de lembrar o card depois de elapsedDays dias.
+func retrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+factor*elapsedDays/stability, decay)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Min(math.Max(v, lo), hi)
+}
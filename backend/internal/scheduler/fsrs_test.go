@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestFSRSFirstReview(t *testing.T) {
+	sched, err := NewFSRS(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	for grade, rating := range map[int]Rating{1: Again, 3: Hard, 4: Good, 5: Easy} {
+		review := model.CardReview{}
+		sched.Schedule(&review, grade, now)
+		if want := DefaultWeights[rating-1]; review.Stability != want {
+			t.Errorf("grade %d: stability = %v, want %v", grade, review.Stability, want)
+		}
+		// Com retenção de 90% o intervalo é a própria estabilidade, arredondada
+		if want := int(math.Max(math.Round(review.Stability), 1)); review.IntervalDays != want {
+			t.Errorf("grade %d: interval = %d, want %d", grade, review.IntervalDays, want)
+		}
+	}
+}
+
+func TestFSRSRecallGrowsAndLapseShrinks(t *testing.T) {
+	sched, _ := NewFSRS(nil, 0.9)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	review := model.CardReview{}
+	sched.Schedule(&review, 4, now)
+	first := review.Stability
+
+	now = now.AddDate(0, 0, review.IntervalDays)
+	sched.Schedule(&review, 4, now)
+	if review.Stability <= first {
+		t.Fatalf("stability after a recall = %v, want more than %v", review.Stability, first)
+	}
+	recalled := review.Stability
+
+	now = now.AddDate(0, 0, review.IntervalDays)
+	sched.Schedule(&review, 1, now)
+	if review.Stability >= recalled || review.Repetitions != 0 {
+		t.Errorf("after a lapse: stability = %v (was %v), repetitions = %d", review.Stability, recalled, review.Repetitions)
+	}
+}
+
+func TestFSRSHigherRetentionShortensIntervals(t *testing.T) {
+	relaxed, _ := NewFSRS(nil, 0.8)
+	strict, _ := NewFSRS(nil, 0.95)
+	now := time.Now()
+
+	a, b := model.CardReview{}, model.CardReview{}
+	relaxed.Schedule(&a, 5, now)
+	strict.Schedule(&b, 5, now)
+	if b.IntervalDays >= a.IntervalDays {
+		t.Errorf("interval at 95%% retention = %d, want less than %d at 80%%", b.IntervalDays, a.IntervalDays)
+	}
+}
+
+func TestNewFSRSValidates(t *testing.T) {
+	if _, err := NewFSRS([]float64{1, 2, 3}, 0.9); err == nil {
+		t.Error("expected an error for the wrong number of weights")
+	}
+	if _, err := NewFSRS(nil, 1.2); err == nil {
+		t.Error("expected an error for a retention above 1")
+	}
+}
+
+// syntheticLogs gera históricos de cards revisados sempre no dia previsto, com uma falha a cada
+// quatro revisões, só para exercitar o otimizador.
+func syntheticLogs(cards, reviewsPerCard int) []model.ReviewLog {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	var logs []model.ReviewLog
+	for c := 0; c < cards; c++ {
+		id := uuid.New()
+		at := start
+		interval := 1
+		for r := 0; r < reviewsPerCard; r++ {
+			grade := 4
+			if (c+r)%4 == 3 {
+				grade = 1
+			}
+			logs = append(logs, model.ReviewLog{FlashcardID: id, Grade: grade, ReviewedAt: at})
+			at = at.AddDate(0, 0, interval)
+			interval *= 2
+		}
+	}
+	return logs
+}
+
+func TestOptimizeNeedsHistory(t *testing.T) {
+	logs := syntheticLogs(10, 5)
+	if err := CheckHistory(logs); !errors.Is(err, ErrNotEnoughHistory) {
+		t.Fatalf("CheckHistory = %v, want ErrNotEnoughHistory", err)
+	}
+	if _, err := Optimize(logs, nil); !errors.Is(err, ErrNotEnoughHistory) {
+		t.Fatalf("Optimize = %v, want ErrNotEnoughHistory", err)
+	}
+}
+
+func TestOptimizeImprovesLossWithinBounds(t *testing.T) {
+	logs := syntheticLogs(30, 6)
+	if err := CheckHistory(logs); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Optimize(logs, nil)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(w) != NumWeights {
+		t.Fatalf("got %d weights, want %d", len(w), NumWeights)
+	}
+	for i, v := range w {
+		if v < weightBounds[i][0] || v > weightBounds[i][1] {
+			t.Errorf("weight %d = %v, outside %v", i, v, weightBounds[i])
+		}
+	}
+
+	sequences := groupByFlashcard(logs)
+	before, after := logLoss(DefaultWeights, sequences), logLoss(w, sequences)
+	if after >= before {
+		t.Errorf("log loss = %v after optimizing, want less than %v", after, before)
+	}
+}
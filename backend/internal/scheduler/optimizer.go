@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// MinReviewsForOptimization é o mínimo de revisões com histórico anterior do mesmo card
+// necessário para ajustar os pesos sem simplesmente decorar o ruído.
+const MinReviewsForOptimization = 100
+
+// ErrNotEnoughHistory indica que o usuário ainda não tem revisões suficientes para otimizar o FSRS.
+var ErrNotEnoughHistory = errors.New("not enough review history to optimize FSRS weights")
+
+const (
+	optimizerIterations   = 100
+	optimizerLearningRate = 0.02
+	adamBeta1             = 0.9
+	adamBeta2             = 0.999
+	adamEpsilon           = 1e-8
+)
+
+// weightBounds limita cada peso aos intervalos usados pelo otimizador oficial do FSRS.
+var weightBounds = [NumWeights][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100},
+	{1, 10}, {0.01, 4}, {0.01, 4}, {0, 0.75},
+	{0, 4.5}, {0, 0.8}, {0.01, 3.5}, {0.1, 5},
+	{0.01, 0.25}, {0.01, 0.9}, {0.01, 4}, {0, 1}, {1, 6},
+}
+
+// Optimize ajusta os pesos do FSRS ao histórico de revisões do usuário.
+// O histórico de cada card é reproduzido em ordem e os pesos são ajustados (Adam com
+// gradiente numérico) para minimizar a log-loss entre a retrievability prevista e o
+// resultado real de cada revisão. initial vazio parte dos pesos padrão.
+func Optimize(logs []model.ReviewLog, initial []float64) ([]float64, error) {
+	sequences := groupByFlashcard(logs)
+	if err := checkHistory(sequences); err != nil {
+		return nil, err
+	}
+
+	if len(initial) != NumWeights {
+		initial = DefaultWeights
+	}
+	w := append(weights(nil), initial...)
+
+	m := make([]float64, NumWeights)
+	v := make([]float64, NumWeights)
+	for iter := 1; iter <= optimizerIterations; iter++ {
+		grad := gradient(w, sequences)
+		for i := range w {
+			m[i] = adamBeta1*m[i] + (1-adamBeta1)*grad[i]
+			v[i] = adamBeta2*v[i] + (1-adamBeta2)*grad[i]*grad[i]
+			mHat := m[i] / (1 - math.Pow(adamBeta1, float64(iter)))
+			vHat := v[i] / (1 - math.Pow(adamBeta2, float64(iter)))
+			w[i] = clamp(w[i]-optimizerLearningRate*mHat/(math.Sqrt(vHat)+adamEpsilon), weightBounds[i][0], weightBounds[i][1])
+		}
+	}
+
+	return w, nil
+}
+
+// CheckHistory diz, sem rodar a otimização, se Optimize recusaria os logs com ErrNotEnoughHistory.
+func CheckHistory(logs []model.ReviewLog) error {
+	return checkHistory(groupByFlashcard(logs))
+}
+
+func checkHistory(sequences [][]model.ReviewLog) error {
+	predicted := 0
+	for _, seq := range sequences {
+		predicted += len(seq) - 1
+	}
+	if predicted < MinReviewsForOptimization {
+		return ErrNotEnoughHistory
+	}
+	return nil
+}
+
+// groupByFlashcard separa o histórico por card, em ordem cronológica.
+func groupByFlashcard(logs []model.ReviewLog) [][]model.ReviewLog {
+	byCard := make(map[uuid.UUID][]model.ReviewLog)
+	for _, l := range logs {
+		byCard[l.FlashcardID] = append(byCard[l.FlashcardID], l)
+	}
+
+	sequences := make([][]model.ReviewLog, 0, len(byCard))
+	for _, seq := range byCard {
+		sort.Slice(seq, func(i, j int) bool { return seq[i].ReviewedAt.Before(seq[j].ReviewedAt) })
+		sequences = append(sequences, seq)
+	}
+	return sequences
+}
+
+// logLoss reproduz o histórico com os pesos w e mede o erro médio das previsões.
+func logLoss(w weights, sequences [][]model.ReviewLog) float64 {
+	total, n := 0.0, 0
+	for _, seq := range sequences {
+		s, d := 0.0, 0.0
+		for i, l := range seq {
+			elapsed := 0.0
+			if i > 0 {
+				elapsed = math.Max(l.ReviewedAt.Sub(seq[i-1].ReviewedAt).Hours()/24, 0)
+				r := clamp(retrievability(elapsed, s), 1e-6, 1-1e-6)
+				if l.Grade >= PassingGrade {
+					total -= math.Log(r)
+				} else {
+					total -= math.Log(1 - r)
+				}
+				n++
+			}
+			s, d = w.step(s, d, elapsed, RatingFromGrade(l.Grade))
+		}
+	}
+	return total / float64(n)
+}
+
+// gradient aproxima o gradiente da log-loss por diferenças centrais.
+func gradient(w weights, sequences [][]model.ReviewLog) []float64 {
+	grad := make([]float64, NumWeights)
+	probe := append(weights(nil), w...)
+	for i := range w {
+		h := 1e-4 * math.Max(1, math.Abs(w[i]))
+		probe[i] = w[i] + h
+		up := logLoss(probe, sequences)
+		probe[i] = w[i] - h
+		down := logLoss(probe, sequences)
+		probe[i] = w[i]
+		grad[i] = (up - down) / (2 * h)
+	}
+	return grad
+}
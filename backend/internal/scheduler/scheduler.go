@@ -0,0 +1,39 @@
+// Package scheduler implementa os algoritmos de repetição espaçada (SM-2 e FSRS)
+// usados para decidir quando cada flashcard deve ser revisado novamente.
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+// Nomes dos algoritmos, como gravados em user_settings.scheduler.
+const (
+	SM2  = "sm2"
+	FSRS = "fsrs"
+)
+
+// PassingGrade é a menor nota (escala 0 a 5) considerada como acerto.
+const PassingGrade = 3
+
+// Scheduler calcula o próximo estado de um card a partir da nota dada pelo usuário.
+type Scheduler interface {
+	// Name retorna o identificador do algoritmo (sm2 ou fsrs).
+	Name() string
+	// Schedule atualiza o estado do card em review para uma revisão com nota grade (0 a 5) feita em now.
+	Schedule(review *model.CardReview, grade int, now time.Time)
+}
+
+// New devolve o algoritmo escolhido nas configurações do usuário.
+func New(settings model.UserSettings) (Scheduler, error) {
+	switch settings.Scheduler {
+	case "", SM2:
+		return NewSM2(), nil
+	case FSRS:
+		return NewFSRS(settings.FSRSWeights, settings.DesiredRetention)
+	default:
+		return nil, fmt.Errorf("unknown scheduler: %s", settings.Scheduler)
+	}
+}
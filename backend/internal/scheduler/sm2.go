@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+const (
+	// DefaultEaseFactor é o fator de facilidade inicial de um card no SM-2.
+	DefaultEaseFactor = 2.5
+	// minEaseFactor é o menor fator de facilidade permitido pelo SM-2.
+	minEaseFactor = 1.3
+)
+
+type sm2 struct{}
+
+// NewSM2 cria o agendador SM-2 clássico (SuperMemo 2).
+func NewSM2() Scheduler {
+	return sm2{}
+}
+
+func (sm2) Name() string {
+	return SM2
+}
+
+// Schedule aplica o SM-2: notas abaixo de 3 reiniciam as repetições; o fator de
+// facilidade é ajustado em toda revisão e nunca fica abaixo de 1.3.
+func (sm2) Schedule(review *model.CardReview, grade int, now time.Time) {
+	if review.EaseFactor == 0 {
+		review.EaseFactor = DefaultEaseFactor
+	}
+
+	if grade >= PassingGrade {
+		switch review.Repetitions {
+		case 0:
+			review.IntervalDays = 1
+		case 1:
+			review.IntervalDays = 6
+		default:
+			review.IntervalDays = int(math.Round(float64(review.IntervalDays) * review.EaseFactor))
+		}
+		review.Repetitions++
+	} else {
+		review.Repetitions = 0
+		review.IntervalDays = 1
+	}
+
+	q := float64(5 - grade)
+	review.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if review.EaseFactor < minEaseFactor {
+		review.EaseFactor = minEaseFactor
+	}
+
+	review.LastReviewedAt = &now
+	review.DueAt = now.AddDate(0, 0, review.IntervalDays)
+}
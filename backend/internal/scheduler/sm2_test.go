@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+func TestSM2Intervals(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	review := model.CardReview{}
+	sched := NewSM2()
+
+	// 1 dia, 6 dias e depois intervalo anterior vezes o fator de facilidade
+	for i, want := range []int{1, 6, 15} {
+		sched.Schedule(&review, 4, now)
+		if review.IntervalDays != want {
+			t.Fatalf("review %d: interval = %d, want %d", i+1, review.IntervalDays, want)
+		}
+		if !review.DueAt.Equal(now.AddDate(0, 0, want)) {
+			t.Errorf("review %d: due = %v, want %v", i+1, review.DueAt, now.AddDate(0, 0, want))
+		}
+		if review.LastReviewedAt == nil || !review.LastReviewedAt.Equal(now) {
+			t.Errorf("review %d: last reviewed = %v, want %v", i+1, review.LastReviewedAt, now)
+		}
+	}
+	if review.Repetitions != 3 {
+		t.Errorf("repetitions = %d, want 3", review.Repetitions)
+	}
+}
+
+func TestSM2FailureResets(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	review := model.CardReview{EaseFactor: DefaultEaseFactor, Repetitions: 4, IntervalDays: 30}
+
+	NewSM2().Schedule(&review, 1, now)
+	if review.Repetitions != 0 || review.IntervalDays != 1 {
+		t.Errorf("after a failure: repetitions = %d, interval = %d, want 0 and 1", review.Repetitions, review.IntervalDays)
+	}
+	if review.EaseFactor >= DefaultEaseFactor {
+		t.Errorf("ease factor = %v, want it lowered from %v", review.EaseFactor, DefaultEaseFactor)
+	}
+}
+
+func TestSM2EaseFactorFloor(t *testing.T) {
+	review := model.CardReview{EaseFactor: minEaseFactor}
+	NewSM2().Schedule(&review, 0, time.Now())
+	if review.EaseFactor != minEaseFactor {
+		t.Errorf("ease factor = %v, want the floor %v", review.EaseFactor, minEaseFactor)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AuthorizeUser garante que o usuário autenticado só acesse os próprios dados.
+func AuthorizeUser(requesterID uuid.UUID, ownerID uuid.UUID) error {
+	if requesterID != ownerID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeSet busca o set e confere se ele pertence ao usuário.
+func authorizeSet(ctx context.Context, setRepo repository.FlashcardSetRepository, userID uuid.UUID, setID uuid.UUID) (model.FlashcardSet, error) {
+	set, err := setRepo.GetByID(ctx, setID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.FlashcardSet{}, ErrNotFound
+	}
+	if err != nil {
+		return model.FlashcardSet{}, err
+	}
+	if err := AuthorizeUser(userID, set.UserID); err != nil {
+		return model.FlashcardSet{}, err
+	}
+	return set, nil
+}
+
+// authorizeFlashcard busca o card e confere se o set dele pertence ao usuário.
+func authorizeFlashcard(ctx context.Context, flashcardRepo repository.FlashcardRepository, setRepo repository.FlashcardSetRepository, userID uuid.UUID, flashcardID uuid.UUID) (model.Flashcard, error) {
+	card, err := flashcardRepo.GetByID(ctx, flashcardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Flashcard{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Flashcard{}, err
+	}
+	if _, err := authorizeSet(ctx, setRepo, userID, card.FlashcardSetID); err != nil {
+		return model.Flashcard{}, err
+	}
+	return card, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrNotFound indica que o recurso solicitado não existe.
+var ErrNotFound = errors.New("resource not found")
+
+// ErrForbidden indica que o recurso existe mas pertence a outro usuário.
+var ErrForbidden = errors.New("you do not have access to this resource")
+
+// ErrSessionClosed indica que a sessão de estudo já foi encerrada e não aceita novas respostas.
+var ErrSessionClosed = errors.New("study session already finished")
+
+// ErrInvalidFlashcards indica que a lista de cards enviada não corresponde aos cards do set
+// (card de outro set, ID repetido ou, na reordenação, card faltando).
+var ErrInvalidFlashcards = errors.New("flashcard ids do not match the cards of the set")
+
+// ErrJobFinished indica que o job já terminou e não pode mais ser cancelado.
+var ErrJobFinished = errors.New("generation job already finished")
+
+// ErrCardLimitExceeded indica que o pedido de geração passou do limite de cards do plano do usuário.
+var ErrCardLimitExceeded = errors.New("card count exceeds the limit of your plan")
+
+// ErrUnsupportedSource indica um arquivo enviado em formato que a geração não sabe ler.
+var ErrUnsupportedSource = errors.New("unsupported file type")
+
+// ErrSourceTooLarge indica um arquivo enviado acima do limite de tamanho.
+var ErrSourceTooLarge = errors.New("file is too large")
+
+// ErrOptimizeCooldown indica que os pesos do FSRS já foram otimizados há pouco tempo.
+var ErrOptimizeCooldown = errors.New("FSRS weights were optimized recently, try again later")
+
+// notFoundIfNoRows traduz sql.ErrNoRows do repositório para ErrNotFound.
+func notFoundIfNoRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
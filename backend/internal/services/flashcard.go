@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
@@ -10,66 +11,235 @@ import (
 
 type FlashcardService interface {
     GenerateAndStoreFlashcards(ctx context.Context, frontsBacks []model.Flashcard, setID uuid.UUID) ([]model.Flashcard, error)
-    GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]model.Flashcard, error)
-    GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string) ([]model.Flashcard, error)
-    GetAllUserFlashcards(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSetWithFlashcards, error)
+    CreateSetWithFlashcards(ctx context.Context, set model.FlashcardSet, cards []model.Flashcard) (model.FlashcardSet, []model.Flashcard, error)
+    GetAllBySetID(ctx context.Context, userID uuid.UUID, setID uuid.UUID, page model.PageRequest) ([]model.Flashcard, string, error)
+    GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string, page model.PageRequest) ([]model.Flashcard, string, error)
+    GetAllUserFlashcards(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetWithFlashcards, string, error)
+    Create(ctx context.Context, userID uuid.UUID, setID uuid.UUID, input model.FlashcardInput) (model.Flashcard, error)
+    Update(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID, patch model.FlashcardPatch) (model.Flashcard, error)
+    Delete(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) error
+    Reorder(ctx context.Context, userID uuid.UUID, setID uuid.UUID, orderedIDs []uuid.UUID) ([]model.Flashcard, error)
+    Move(ctx context.Context, userID uuid.UUID, setID uuid.UUID, req model.MoveRequest) ([]model.Flashcard, error)
+    Copy(ctx context.Context, userID uuid.UUID, setID uuid.UUID, req model.CopyRequest) (model.CopyResult, error)
 }
 
 type flashcardService struct {
     repo repository.FlashcardRepository
     setRepo repository.FlashcardSetRepository
+    tx repository.Transactor
 }
 
-func NewFlashcardService(repo repository.FlashcardRepository, setRepo repository.FlashcardSetRepository) FlashcardService {
-    return &flashcardService{repo: repo, setRepo: setRepo}
+func NewFlashcardService(repo repository.FlashcardRepository, setRepo repository.FlashcardSetRepository, tx repository.Transactor) FlashcardService {
+    return &flashcardService{repo: repo, setRepo: setRepo, tx: tx}
 }
 
-// Este método recebe uma lista de flashcards (apenas com os dados de front/back) e atribui 
-// ordem, setID e persiste cada um.
+// Este método recebe uma lista de flashcards (apenas com os dados de front/back), atribui 
+// ordem e setID e persiste todos com um insert em lote: ou todos entram, ou nenhum.
+// O resultado mantém a ordem da entrada, já com os IDs gerados.
 func (s *flashcardService) GenerateAndStoreFlashcards(ctx context.Context, cards []model.Flashcard, setID uuid.UUID) ([]model.Flashcard, error) {
-    var result []model.Flashcard
+    result := make([]model.Flashcard, len(cards))
     for i, card := range cards {
         card.FlashcardSetID = setID
         card.CardOrder = i + 1
-        if err := s.repo.Create(ctx, &card); err != nil {
-            return nil, err
-        }
-        result = append(result, card)
+        result[i] = card
+    }
+    if err := s.repo.CreateBatch(ctx, result); err != nil {
+        return nil, err
     }
     return result, nil
 }
 
-func (s *flashcardService) GetAllBySetID(ctx context.Context, setID uuid.UUID) ([]model.Flashcard, error) {
-    return s.repo.GetAllBySetID(ctx, setID)
+// CreateSetWithFlashcards cria o set e todos os cards na mesma transação, para que uma falha
+// no meio do caminho não deixe um set vazio ou pela metade no banco. Deve ser chamado só depois
+// que os cards já foram gerados.
+func (s *flashcardService) CreateSetWithFlashcards(ctx context.Context, set model.FlashcardSet, cards []model.Flashcard) (model.FlashcardSet, []model.Flashcard, error) {
+    var stored []model.Flashcard
+    err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+        setID, err := s.setRepo.Create(ctx, &set)
+        if err != nil {
+            return err
+        }
+        if stored, err = s.GenerateAndStoreFlashcards(ctx, cards, setID); err != nil {
+            return err
+        }
+        // Relê o set para devolver os timestamps gerados pelo banco
+        set, err = s.setRepo.GetByID(ctx, setID)
+        return err
+    })
+    if err != nil {
+        return model.FlashcardSet{}, nil, err
+    }
+    return set, stored, nil
 }
 
-func (s *flashcardService) GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string) ([]model.Flashcard, error) {
-    return s.repo.GetFlashcardsByTopic(ctx, userID, topic)
+// GetAllBySetID retorna uma página dos cards do set, desde que ele pertença ao usuário,
+// e o cursor da próxima página.
+func (s *flashcardService) GetAllBySetID(ctx context.Context, userID uuid.UUID, setID uuid.UUID, page model.PageRequest) ([]model.Flashcard, string, error) {
+    if _, err := authorizeSet(ctx, s.setRepo, userID, setID); err != nil {
+        return nil, "", err
+    }
+    return s.repo.ListBySetID(ctx, setID, page)
+}
+
+func (s *flashcardService) GetFlashcardsByTopic(ctx context.Context, userID uuid.UUID, topic string, page model.PageRequest) ([]model.Flashcard, string, error) {
+    return s.repo.GetFlashcardsByTopic(ctx, userID, topic, page)
 }
 
-func (s *flashcardService) GetAllUserFlashcards(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSetWithFlashcards, error) {
-	sets, err := s.setRepo.GetAllByUserID(ctx, userID)
+// GetAllUserFlashcards pagina pelos sets: cada página traz os sets e todos os cards deles.
+func (s *flashcardService) GetAllUserFlashcards(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetWithFlashcards, string, error) {
+	sets, next, err := s.setRepo.GetAllByUserID(ctx, userID, filter, page)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	setIDs := make([]uuid.UUID, len(sets))
+	for i, set := range sets {
+		setIDs[i] = set.ID
+	}
+	// Uma única query para os cards de todos os sets, em vez de uma por set
+	cardsBySet, err := s.repo.GetAllBySetIDs(ctx, setIDs)
+	if err != nil {
+		return nil, "", err
 	}
 
 	var result []model.FlashcardSetWithFlashcards
 
 	for _, set := range sets {
-		cards, err := s.repo.GetAllBySetID(ctx, set.ID)
-		if err != nil {
-			return nil, err
-		}
+		cards := cardsBySet[set.ID]
 
 		result = append(result, model.FlashcardSetWithFlashcards{
 			ID:          set.ID.String(),
             UserID:      set.UserID.String(),
             Topic:       set.Topic,
+            Difficulty:  set.Difficulty,
             CreatedAt:   set.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:   set.UpdatedAt.Format("2006-01-02 15:04:05"),
 			Flashcards:   cards,
 		})
 	}
 
+	return result, next, nil
+}
+
+// Create adiciona um card escrito pelo usuário ao final do set.
+func (s *flashcardService) Create(ctx context.Context, userID uuid.UUID, setID uuid.UUID, input model.FlashcardInput) (model.Flashcard, error) {
+	if _, err := authorizeSet(ctx, s.setRepo, userID, setID); err != nil {
+		return model.Flashcard{}, err
+	}
+
+	card := model.Flashcard{
+		FlashcardSetID: setID,
+		QuestionText:   input.QuestionText,
+		AnswerText:     input.AnswerText,
+	}
+	if err := s.repo.Append(ctx, &card); err != nil {
+		return model.Flashcard{}, notFoundIfNoRows(err)
+	}
+	return card, nil
+}
+
+// Update altera os campos informados de um card do usuário, por exemplo para corrigir uma resposta gerada errada.
+func (s *flashcardService) Update(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID, patch model.FlashcardPatch) (model.Flashcard, error) {
+	card, err := authorizeFlashcard(ctx, s.repo, s.setRepo, userID, flashcardID)
+	if err != nil {
+		return model.Flashcard{}, err
+	}
+
+	if patch.QuestionText != nil {
+		card.QuestionText = *patch.QuestionText
+	}
+	if patch.AnswerText != nil {
+		card.AnswerText = *patch.AnswerText
+	}
+
+	if err := s.repo.Update(ctx, &card); err != nil {
+		return model.Flashcard{}, notFoundIfNoRows(err)
+	}
+	return card, nil
+}
+
+func (s *flashcardService) Delete(ctx context.Context, userID uuid.UUID, flashcardID uuid.UUID) error {
+	if _, err := authorizeFlashcard(ctx, s.repo, s.setRepo, userID, flashcardID); err != nil {
+		return err
+	}
+
+	return notFoundIfNoRows(s.repo.Delete(ctx, flashcardID))
+}
+
+// Reorder aplica uma nova ordem aos cards do set. A lista precisa conter todos os cards do set, cada um uma vez.
+func (s *flashcardService) Reorder(ctx context.Context, userID uuid.UUID, setID uuid.UUID, orderedIDs []uuid.UUID) ([]model.Flashcard, error) {
+	if _, err := authorizeSet(ctx, s.setRepo, userID, setID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Reorder(ctx, setID, orderedIDs); err != nil {
+		return nil, cardsError(err)
+	}
+	return s.repo.GetAllBySetID(ctx, setID)
+}
+
+// Move transfere cards do set para outro set do usuário. O histórico de revisão acompanha o card,
+// já que ele mantém o mesmo ID.
+func (s *flashcardService) Move(ctx context.Context, userID uuid.UUID, setID uuid.UUID, req model.MoveRequest) ([]model.Flashcard, error) {
+	if req.TargetSetID == setID {
+		return nil, ErrInvalidFlashcards
+	}
+	if _, err := authorizeSet(ctx, s.setRepo, userID, setID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeSet(ctx, s.setRepo, userID, req.TargetSetID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Move(ctx, setID, req.TargetSetID, req.FlashcardIDs); err != nil {
+		return nil, cardsError(err)
+	}
+	return s.repo.GetAllBySetID(ctx, req.TargetSetID)
+}
+
+// Copy duplica cards do set, com IDs novos e sem histórico de revisão, em um set existente do usuário
+// ou em um set novo criado com NewSetTopic.
+func (s *flashcardService) Copy(ctx context.Context, userID uuid.UUID, setID uuid.UUID, req model.CopyRequest) (model.CopyResult, error) {
+	source, err := authorizeSet(ctx, s.setRepo, userID, setID)
+	if err != nil {
+		return model.CopyResult{}, err
+	}
+
+	if req.TargetSetID != nil {
+		if _, err := authorizeSet(ctx, s.setRepo, userID, *req.TargetSetID); err != nil {
+			return model.CopyResult{}, err
+		}
+		copies, err := s.repo.Copy(ctx, setID, *req.TargetSetID, req.FlashcardIDs)
+		if err != nil {
+			return model.CopyResult{}, cardsError(err)
+		}
+		return model.CopyResult{TargetSetID: *req.TargetSetID, Flashcards: copies}, nil
+	}
+
+	// O set novo, que herda o nível do set de origem, só existe se a cópia der certo
+	var result model.CopyResult
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		targetID, err := s.setRepo.Create(ctx, &model.FlashcardSet{UserID: userID, Topic: *req.NewSetTopic, Difficulty: source.Difficulty})
+		if err != nil {
+			return err
+		}
+		copies, err := s.repo.Copy(ctx, setID, targetID, req.FlashcardIDs)
+		if err != nil {
+			return err
+		}
+		result = model.CopyResult{TargetSetID: targetID, Flashcards: copies}
+		return nil
+	})
+	if err != nil {
+		return model.CopyResult{}, cardsError(err)
+	}
 	return result, nil
 }
+
+// cardsError traduz os erros de ordenação do repositório para os erros do serviço.
+func cardsError(err error) error {
+	if errors.Is(err, repository.ErrCardsMismatch) {
+		return ErrInvalidFlashcards
+	}
+	return notFoundIfNoRows(err)
+}
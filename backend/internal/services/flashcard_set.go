@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
@@ -12,10 +13,16 @@ import (
 type FlashcardSetService interface {
 	// Create cria um novo flashcard set e retorna o ID criado.
 	Create(ctx context.Context, set model.FlashcardSet) (uuid.UUID, error)
-	// GetByID busca um flashcard set pelo ID.
-	GetByID(ctx context.Context, setID uuid.UUID) (model.FlashcardSet, error)
-	// GetAllByUserID busca todos os flashcard sets de um usuário.
-	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSet, error)
+	// GetByID busca um flashcard set pelo ID, desde que ele pertença ao usuário.
+	GetByID(ctx context.Context, userID uuid.UUID, setID uuid.UUID) (model.FlashcardSet, error)
+	// GetAllByUserID busca uma página dos flashcard sets de um usuário que passam no filtro e o cursor da próxima.
+	GetAllByUserID(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSet, string, error)
+	// GetSummaries busca uma página dos sets do usuário com os totais do dashboard (cards, vencidos, novos, último estudo).
+	GetSummaries(ctx context.Context, userID uuid.UUID, now time.Time, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetSummary, string, error)
+	// Update altera os campos informados de um set do usuário.
+	Update(ctx context.Context, userID uuid.UUID, setID uuid.UUID, patch model.FlashcardSetPatch) (model.FlashcardSet, error)
+	// Delete remove um set do usuário junto com todos os cards.
+	Delete(ctx context.Context, userID uuid.UUID, setID uuid.UUID) error
 }
 
 
@@ -34,11 +41,44 @@ func (s *flashcardSetService) Create(ctx context.Context, set model.FlashcardSet
 	return s.repo.Create(ctx, &set)
 }
 
-// GetByID chama o repositório para recuperar um flashcard set.
-func (s *flashcardSetService) GetByID(ctx context.Context, setID uuid.UUID) (model.FlashcardSet, error) {
-	return s.repo.GetByID(ctx, setID)
+// GetByID chama o repositório para recuperar um flashcard set e confere o dono.
+func (s *flashcardSetService) GetByID(ctx context.Context, userID uuid.UUID, setID uuid.UUID) (model.FlashcardSet, error) {
+	return authorizeSet(ctx, s.repo, userID, setID)
 }
 
-func (s *flashcardSetService) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]model.FlashcardSet, error) {
-	return s.repo.GetAllByUserID(ctx, userID)
+func (s *flashcardSetService) GetAllByUserID(ctx context.Context, userID uuid.UUID, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSet, string, error) {
+	return s.repo.GetAllByUserID(ctx, userID, filter, page)
+}
+
+func (s *flashcardSetService) GetSummaries(ctx context.Context, userID uuid.UUID, now time.Time, filter model.FlashcardSetFilter, page model.PageRequest) ([]model.FlashcardSetSummary, string, error) {
+	return s.repo.GetSummariesByUserID(ctx, userID, now, filter, page)
+}
+
+func (s *flashcardSetService) Update(ctx context.Context, userID uuid.UUID, setID uuid.UUID, patch model.FlashcardSetPatch) (model.FlashcardSet, error) {
+	set, err := authorizeSet(ctx, s.repo, userID, setID)
+	if err != nil {
+		return model.FlashcardSet{}, err
+	}
+
+	if patch.Topic != nil {
+		set.Topic = *patch.Topic
+	}
+	if patch.Difficulty != nil {
+		set.Difficulty = nil
+		if *patch.Difficulty != "" {
+			set.Difficulty = patch.Difficulty
+		}
+	}
+
+	if err := s.repo.Update(ctx, &set); err != nil {
+		return model.FlashcardSet{}, notFoundIfNoRows(err)
+	}
+	return set, nil
+}
+
+func (s *flashcardSetService) Delete(ctx context.Context, userID uuid.UUID, setID uuid.UUID) error {
+	if _, err := authorizeSet(ctx, s.repo, userID, setID); err != nil {
+		return err
+	}
+	return notFoundIfNoRows(s.repo.Delete(ctx, setID))
 }
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// fakeSetRepo guarda um único set e o que Update gravou nele.
+type fakeSetRepo struct {
+	repository.FlashcardSetRepository
+	set     model.FlashcardSet
+	updated *model.FlashcardSet
+}
+
+func (r *fakeSetRepo) GetByID(ctx context.Context, setID uuid.UUID) (model.FlashcardSet, error) {
+	return r.set, nil
+}
+
+func (r *fakeSetRepo) Update(ctx context.Context, set *model.FlashcardSet) error {
+	saved := *set
+	r.updated = &saved
+	return nil
+}
+
+func TestFlashcardSetUpdateDifficulty(t *testing.T) {
+	advanced := model.DifficultyAdvanced
+	tests := []struct {
+		name string
+		body string
+		want *model.Difficulty
+	}{
+		{"alias", `{"difficulty": "Easy"}`, ptr(model.DifficultyBeginner)},
+		{"canonical", `{"difficulty": "intermediate"}`, ptr(model.DifficultyIntermediate)},
+		{"empty clears", `{"difficulty": ""}`, nil},
+		{"absent keeps", `{"topic": "Cardiologia"}`, &advanced},
+		{"null keeps", `{"difficulty": null}`, &advanced},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch model.FlashcardSetPatch
+			if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+				t.Fatalf("unmarshal %s: %v", tt.body, err)
+			}
+
+			userID := uuid.New()
+			repo := &fakeSetRepo{set: model.FlashcardSet{ID: uuid.New(), UserID: userID, Topic: "Arritmias", Difficulty: &advanced}}
+			svc := NewFlashcardSetService(repo)
+			set, err := svc.Update(context.Background(), userID, repo.set.ID, patch)
+			if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			for _, got := range []*model.Difficulty{set.Difficulty, repo.updated.Difficulty} {
+				if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+					t.Errorf("difficulty = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFlashcardSetPatchRejectsUnknownDifficulty(t *testing.T) {
+	var patch model.FlashcardSetPatch
+	if err := json.Unmarshal([]byte(`{"difficulty": "impossível"}`), &patch); err != model.ErrInvalidDifficulty {
+		t.Fatalf("err = %v, want ErrInvalidDifficulty", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// JobService enfileira gerações de flashcards e expõe o andamento delas ao dono.
+// O processamento fica a cargo do pool de workers (pacote jobs).
+type JobService interface {
+	// EnqueuePrompt cria um job de geração a partir de um tópico.
+	EnqueuePrompt(ctx context.Context, userID uuid.UUID, req model.PromptRequest) (model.GenerationJob, error)
+	// Get busca um job do usuário.
+	Get(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (model.GenerationJob, error)
+	// Cancel cancela um job do usuário que ainda não terminou.
+	Cancel(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (model.GenerationJob, error)
+}
+
+type jobService struct {
+	repo repository.JobRepository
+}
+
+func NewJobService(repo repository.JobRepository) JobService {
+	return &jobService{repo: repo}
+}
+
+func (s *jobService) EnqueuePrompt(ctx context.Context, userID uuid.UUID, req model.PromptRequest) (model.GenerationJob, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return model.GenerationJob{}, err
+	}
+
+	job := model.GenerationJob{UserID: userID, Kind: model.JobKindPrompt, Payload: payload}
+	if err := s.repo.Create(ctx, &job); err != nil {
+		return model.GenerationJob{}, err
+	}
+	return job, nil
+}
+
+func (s *jobService) Get(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (model.GenerationJob, error) {
+	job, err := s.repo.GetByID(ctx, jobID)
+	if err != nil {
+		return model.GenerationJob{}, notFoundIfNoRows(err)
+	}
+	if err := AuthorizeUser(userID, job.UserID); err != nil {
+		return model.GenerationJob{}, err
+	}
+	return job, nil
+}
+
+// Cancel marca o job como cancelado. Um worker que esteja rodando o job percebe no próximo
+// heartbeat, interrompe a chamada ao LLM e descarta o resultado.
+func (s *jobService) Cancel(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (model.GenerationJob, error) {
+	if _, err := s.Get(ctx, userID, jobID); err != nil {
+		return model.GenerationJob{}, err
+	}
+
+	job, err := s.repo.Cancel(ctx, jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.GenerationJob{}, ErrJobFinished
+	}
+	return job, err
+}
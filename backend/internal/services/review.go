@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+// ReviewService define as operações de estudo (revisão espaçada) dos flashcards.
+type ReviewService interface {
+	// Review registra a resposta do usuário a um card no histórico e reagenda a próxima revisão
+	// com o algoritmo escolhido nas configurações dele (SM-2 ou FSRS).
+	Review(ctx context.Context, userID uuid.UUID, answer model.ReviewAnswer) (model.CardReview, error)
+}
+
+type reviewService struct {
+	repo            repository.ReviewRepository
+	flashcardRepo   repository.FlashcardRepository
+	setRepo         repository.FlashcardSetRepository
+	settingsService SettingsService
+	tx              repository.Transactor
+}
+
+// NewReviewService cria uma nova instância de ReviewService.
+func NewReviewService(repo repository.ReviewRepository, flashcardRepo repository.FlashcardRepository, setRepo repository.FlashcardSetRepository, settingsService SettingsService, tx repository.Transactor) ReviewService {
+	return &reviewService{repo: repo, flashcardRepo: flashcardRepo, setRepo: setRepo, settingsService: settingsService, tx: tx}
+}
+
+func (s *reviewService) Review(ctx context.Context, userID uuid.UUID, answer model.ReviewAnswer) (model.CardReview, error) {
+	if _, err := authorizeFlashcard(ctx, s.flashcardRepo, s.setRepo, userID, answer.FlashcardID); err != nil {
+		return model.CardReview{}, err
+	}
+
+	settings, err := s.settingsService.Get(ctx, userID)
+	if err != nil {
+		return model.CardReview{}, err
+	}
+	sched, err := scheduler.New(settings)
+	if err != nil {
+		return model.CardReview{}, err
+	}
+
+	// Estado, agenda e histórico mudam juntos: a linha do card fica travada até o log ser gravado
+	var review model.CardReview
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var stateBefore *model.ScheduleState
+		var err error
+		review, err = s.repo.GetByFlashcardForUpdate(ctx, userID, answer.FlashcardID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Primeira revisão do card: começa do estado inicial
+			review = model.CardReview{
+				UserID:      userID,
+				FlashcardID: answer.FlashcardID,
+				EaseFactor:  scheduler.DefaultEaseFactor,
+			}
+		} else if err != nil {
+			return err
+		} else {
+			state := review.State()
+			stateBefore = &state
+		}
+
+		answeredAt := answerTime(answer, review.LastReviewedAt, time.Now())
+		sched.Schedule(&review, answer.Grade, answeredAt)
+
+		if err := s.repo.Upsert(ctx, &review); err != nil {
+			return err
+		}
+
+		stateAfter := review.State()
+		return s.repo.CreateLog(ctx, &model.ReviewLog{
+			UserID:         userID,
+			FlashcardID:    answer.FlashcardID,
+			SessionID:      answer.SessionID,
+			Grade:          answer.Grade,
+			ResponseTimeMs: answer.ResponseTimeMs,
+			Scheduler:      sched.Name(),
+			StateBefore:    stateBefore,
+			StateAfter:     &stateAfter,
+			ReviewedAt:     answeredAt,
+		})
+	})
+	if err != nil {
+		return model.CardReview{}, err
+	}
+
+	return review, nil
+}
+
+// answerTime decide o horário da resposta: o informado pelo cliente, mas nunca no futuro, nunca
+// antes da última revisão do card (o histórico fica em ordem) e nunca antes de answer.NotBefore.
+func answerTime(answer model.ReviewAnswer, lastReviewedAt *time.Time, now time.Time) time.Time {
+	answeredAt := answer.AnsweredAt
+	if answeredAt.IsZero() || answeredAt.After(now) {
+		return now
+	}
+	if answeredAt.Before(answer.NotBefore) {
+		answeredAt = answer.NotBefore
+	}
+	if lastReviewedAt != nil && answeredAt.Before(*lastReviewedAt) {
+		answeredAt = *lastReviewedAt
+	}
+	return answeredAt
+}
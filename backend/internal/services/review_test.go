@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+)
+
+func TestAnswerTime(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	lastReviewed := now.Add(-time.Hour)
+	sessionStart := now.Add(-30 * time.Minute)
+
+	tests := []struct {
+		name         string
+		answer       model.ReviewAnswer
+		lastReviewed *time.Time
+		want         time.Time
+	}{
+		{"missing uses now", model.ReviewAnswer{}, &lastReviewed, now},
+		{"future uses now", model.ReviewAnswer{AnsweredAt: now.Add(time.Minute)}, nil, now},
+		{"past is kept", model.ReviewAnswer{AnsweredAt: now.Add(-2 * time.Hour)}, nil, now.Add(-2 * time.Hour)},
+		{"before last review", model.ReviewAnswer{AnsweredAt: now.Add(-2 * time.Hour)}, &lastReviewed, lastReviewed},
+		{"before session start", model.ReviewAnswer{AnsweredAt: now.Add(-2 * time.Hour), NotBefore: sessionStart}, &lastReviewed, sessionStart},
+		{"inside session", model.ReviewAnswer{AnsweredAt: now.Add(-time.Minute), NotBefore: sessionStart}, &lastReviewed, now.Add(-time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := answerTime(tt.answer, tt.lastReviewed, now); !got.Equal(tt.want) {
+				t.Errorf("answerTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
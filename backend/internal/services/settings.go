@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+// SettingsService define as operações sobre as configurações de estudo do usuário.
+type SettingsService interface {
+	// Get retorna as configurações do usuário, ou os valores padrão se ele nunca as alterou.
+	Get(ctx context.Context, userID uuid.UUID) (model.UserSettings, error)
+	// Update altera apenas os campos informados na requisição.
+	Update(ctx context.Context, userID uuid.UUID, req model.UpdateSettingsRequest) (model.UserSettings, error)
+	// OptimizeFSRS ajusta os pesos do FSRS ao histórico de revisões do usuário e os salva.
+	// Roda no máximo uma vez a cada OptimizeCooldown por usuário (ErrOptimizeCooldown).
+	OptimizeFSRS(ctx context.Context, userID uuid.UUID) (model.UserSettings, error)
+}
+
+type settingsService struct {
+	repo       repository.SettingsRepository
+	reviewRepo repository.ReviewRepository
+}
+
+// NewSettingsService cria uma nova instância de SettingsService.
+func NewSettingsService(repo repository.SettingsRepository, reviewRepo repository.ReviewRepository) SettingsService {
+	return &settingsService{repo: repo, reviewRepo: reviewRepo}
+}
+
+func (s *settingsService) Get(ctx context.Context, userID uuid.UUID) (model.UserSettings, error) {
+	settings, err := s.repo.GetByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultSettings(userID), nil
+	}
+	return settings, err
+}
+
+func (s *settingsService) Update(ctx context.Context, userID uuid.UUID, req model.UpdateSettingsRequest) (model.UserSettings, error) {
+	settings, err := s.Get(ctx, userID)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+
+	if req.Scheduler != nil {
+		settings.Scheduler = *req.Scheduler
+	}
+	if req.DesiredRetention != nil {
+		settings.DesiredRetention = *req.DesiredRetention
+	}
+	if req.NewCardsPerDay != nil {
+		settings.NewCardsPerDay = *req.NewCardsPerDay
+	}
+	if req.ReviewsPerDay != nil {
+		settings.ReviewsPerDay = *req.ReviewsPerDay
+	}
+
+	if err := s.repo.Upsert(ctx, &settings); err != nil {
+		return model.UserSettings{}, err
+	}
+	return settings, nil
+}
+
+// A otimização roda na requisição e custa CPU proporcional ao histórico: ela usa no máximo
+// maxOptimizeLogs revisões (dos cards estudados mais recentemente) e pode ser pedida de novo
+// só depois de OptimizeCooldown.
+const (
+	maxOptimizeLogs  = 5000
+	OptimizeCooldown = time.Hour
+)
+
+func (s *settingsService) OptimizeFSRS(ctx context.Context, userID uuid.UUID) (model.UserSettings, error) {
+	settings, err := s.repo.GetByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A marcação da última otimização fica em user_settings, então a linha precisa existir
+		settings = defaultSettings(userID)
+		err = s.repo.Upsert(ctx, &settings)
+	}
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+
+	logs, err := s.reviewRepo.GetLogsByUserID(ctx, userID, maxOptimizeLogs)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+	// Histórico insuficiente não gasta o intervalo entre otimizações
+	if err := scheduler.CheckHistory(logs); err != nil {
+		return model.UserSettings{}, err
+	}
+
+	claimed, err := s.repo.ClaimOptimization(ctx, userID, OptimizeCooldown)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+	if !claimed {
+		return model.UserSettings{}, ErrOptimizeCooldown
+	}
+
+	weights, err := scheduler.Optimize(logs, settings.FSRSWeights)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+
+	settings.FSRSWeights = weights
+	if err := s.repo.Upsert(ctx, &settings); err != nil {
+		return model.UserSettings{}, err
+	}
+	return s.Get(ctx, userID)
+}
+
+const (
+	defaultNewCardsPerDay = 20
+	defaultReviewsPerDay  = 200
+)
+
+func defaultSettings(userID uuid.UUID) model.UserSettings {
+	return model.UserSettings{
+		UserID:           userID,
+		Scheduler:        scheduler.SM2,
+		DesiredRetention: scheduler.DefaultDesiredRetention,
+		NewCardsPerDay:   defaultNewCardsPerDay,
+		ReviewsPerDay:    defaultReviewsPerDay,
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/ingestion"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/storage"
+	"github.com/google/uuid"
+)
+
+// SourceService recebe os arquivos de material de estudo, guarda o conteúdo no blob store e
+// os metadados no banco, e os devolve para a geração.
+type SourceService interface {
+	// Upload lê r até o fim, gravando no blob store conforme chega. O formato é detectado pelo
+	// conteúdo; formatos não suportados devolvem ErrUnsupportedSource e arquivos acima do
+	// limite, ErrSourceTooLarge.
+	Upload(ctx context.Context, userID uuid.UUID, fileName string, r io.Reader) (model.Source, error)
+	// Get busca os metadados de um arquivo do usuário.
+	Get(ctx context.Context, userID uuid.UUID, sourceID uuid.UUID) (model.Source, error)
+	// Load busca um arquivo do usuário junto com o conteúdo.
+	Load(ctx context.Context, userID uuid.UUID, sourceID uuid.UUID) (model.Source, []byte, error)
+}
+
+type sourceService struct {
+	repo  repository.SourceRepository
+	store storage.Store
+}
+
+func NewSourceService(repo repository.SourceRepository, store storage.Store) SourceService {
+	return &sourceService{repo: repo, store: store}
+}
+
+func (s *sourceService) Upload(ctx context.Context, userID uuid.UUID, fileName string, r io.Reader) (model.Source, error) {
+	br := bufio.NewReaderSize(r, ingestion.SniffLen)
+	head, err := br.Peek(ingestion.SniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return model.Source{}, err
+	}
+	if len(head) == 0 {
+		return model.Source{}, fmt.Errorf("%w: empty file", ErrUnsupportedSource)
+	}
+
+	fileName = cleanFileName(fileName)
+	contentType, mimeType, ok := ingestion.DetectContentType(fileName, head)
+	if !ok {
+		return model.Source{}, fmt.Errorf("%w: %s", ErrUnsupportedSource, mimeType)
+	}
+	limit := int64(model.MaxSourceBytes)
+	if contentType == "image" {
+		limit = ingestion.MaxImageBytes
+	}
+
+	source := model.Source{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		MIMEType:    mimeType,
+	}
+	source.StorageKey = "sources/" + userID.String() + "/" + source.ID.String()
+
+	size, err := s.store.Put(ctx, source.StorageKey, &limitedReader{r: br, remaining: limit})
+	if errors.Is(err, ErrSourceTooLarge) {
+		return model.Source{}, fmt.Errorf("%w: the limit for %s files is %d MB", ErrSourceTooLarge, contentType, limit>>20)
+	}
+	if err != nil {
+		return model.Source{}, err
+	}
+	source.SizeBytes = size
+
+	if err := s.repo.Create(ctx, &source); err != nil {
+		// Sem a linha no banco ninguém acha o blob; apaga mesmo se a requisição foi cancelada
+		if delErr := s.store.Delete(context.WithoutCancel(ctx), source.StorageKey); delErr != nil {
+			log.Printf("Erro ao apagar o blob órfão %s: %v", source.StorageKey, delErr)
+		}
+		return model.Source{}, err
+	}
+	return source, nil
+}
+
+func (s *sourceService) Get(ctx context.Context, userID uuid.UUID, sourceID uuid.UUID) (model.Source, error) {
+	source, err := s.repo.GetByID(ctx, sourceID)
+	if err != nil {
+		return model.Source{}, notFoundIfNoRows(err)
+	}
+	if err := AuthorizeUser(userID, source.UserID); err != nil {
+		return model.Source{}, err
+	}
+	return source, nil
+}
+
+func (s *sourceService) Load(ctx context.Context, userID uuid.UUID, sourceID uuid.UUID) (model.Source, []byte, error) {
+	source, err := s.Get(ctx, userID, sourceID)
+	if err != nil {
+		return model.Source{}, nil, err
+	}
+
+	blob, err := s.store.Open(ctx, source.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return model.Source{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return model.Source{}, nil, err
+	}
+	defer blob.Close()
+
+	data, err := io.ReadAll(io.LimitReader(blob, model.MaxSourceBytes+1))
+	if err != nil {
+		return model.Source{}, nil, err
+	}
+	return source, data, nil
+}
+
+// cleanFileName guarda só o nome do arquivo, sem pastas, limitado a 255 bytes.
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "upload"
+	}
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return name
+}
+
+// limitedReader falha com ErrSourceTooLarge quando r passa de remaining bytes, em vez de
+// truncar em silêncio como io.LimitReader.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrSourceTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, ErrSourceTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLimitedReader(t *testing.T) {
+	const limit = 10
+	tests := []struct {
+		size    int
+		wantErr error
+	}{
+		{0, nil},
+		{limit - 1, nil},
+		{limit, nil},
+		{limit + 1, ErrSourceTooLarge},
+		{4 * limit, ErrSourceTooLarge},
+	}
+	for _, tt := range tests {
+		// Leituras de um byte: o corte não pode depender do tamanho do buffer de quem lê
+		for _, r := range []io.Reader{strings.NewReader(strings.Repeat("x", tt.size)), iotest.OneByteReader(strings.NewReader(strings.Repeat("x", tt.size)))} {
+			data, err := io.ReadAll(&limitedReader{r: r, remaining: limit})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("size %d: err = %v, want %v", tt.size, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(data) != tt.size {
+				t.Errorf("size %d: read %d bytes", tt.size, len(data))
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
+	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// StudyService define as operações da rotina de estudo do usuário.
+type StudyService interface {
+	// GetDueQueue monta a fila do dia em todos os sets do usuário, respeitando os limites
+	// diários de cards novos e de revisões. O dia é calculado no fuso horário de now.
+	GetDueQueue(ctx context.Context, userID uuid.UUID, now time.Time) (model.DueQueue, error)
+	// StartSession abre uma sessão de estudo para um ou mais sets do usuário.
+	StartSession(ctx context.Context, userID uuid.UUID, setIDs []uuid.UUID) (model.StudySession, error)
+	// GetSession busca uma sessão do usuário.
+	GetSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (model.StudySession, error)
+	// RecordAnswer registra a resposta a um card de um dos sets da sessão e reagenda o card.
+	RecordAnswer(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, req model.SessionAnswerRequest) (model.CardReview, error)
+	// FinishSession encerra a sessão e devolve o resumo das respostas.
+	FinishSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (model.StudySession, model.SessionSummary, error)
+}
+
+type studyService struct {
+	reviewRepo      repository.ReviewRepository
+	sessionRepo     repository.StudySessionRepository
+	setRepo         repository.FlashcardSetRepository
+	flashcardRepo   repository.FlashcardRepository
+	reviewService   ReviewService
+	settingsService SettingsService
+}
+
+// NewStudyService cria uma nova instância de StudyService.
+func NewStudyService(reviewRepo repository.ReviewRepository, sessionRepo repository.StudySessionRepository, setRepo repository.FlashcardSetRepository,
+	flashcardRepo repository.FlashcardRepository, reviewService ReviewService, settingsService SettingsService) StudyService {
+	return &studyService{
+		reviewRepo:      reviewRepo,
+		sessionRepo:     sessionRepo,
+		setRepo:         setRepo,
+		flashcardRepo:   flashcardRepo,
+		reviewService:   reviewService,
+		settingsService: settingsService,
+	}
+}
+
+func (s *studyService) GetDueQueue(ctx context.Context, userID uuid.UUID, now time.Time) (model.DueQueue, error) {
+	settings, err := s.settingsService.Get(ctx, userID)
+	if err != nil {
+		return model.DueQueue{}, err
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	// Desconta o que o usuário já estudou hoje dos limites diários
+	newToday, reviewsToday, err := s.reviewRepo.CountStudiedSince(ctx, userID, startOfDay)
+	if err != nil {
+		return model.DueQueue{}, err
+	}
+	newLimit := max(settings.NewCardsPerDay-newToday, 0)
+	reviewLimit := max(settings.ReviewsPerDay-reviewsToday, 0)
+
+	queue := model.DueQueue{Cards: []model.DueCard{}}
+
+	if reviewLimit > 0 {
+		due, err := s.reviewRepo.GetDueCards(ctx, userID, endOfDay, reviewLimit)
+		if err != nil {
+			return model.DueQueue{}, err
+		}
+		queue.Cards = append(queue.Cards, due...)
+		queue.ReviewCount = len(due)
+	}
+
+	if newLimit > 0 {
+		fresh, err := s.reviewRepo.GetNewCards(ctx, userID, newLimit)
+		if err != nil {
+			return model.DueQueue{}, err
+		}
+		queue.Cards = append(queue.Cards, fresh...)
+		queue.NewCount = len(fresh)
+	}
+
+	return queue, nil
+}
+
+func (s *studyService) StartSession(ctx context.Context, userID uuid.UUID, setIDs []uuid.UUID) (model.StudySession, error) {
+	var unique []uuid.UUID
+	for _, setID := range setIDs {
+		if slices.Contains(unique, setID) {
+			continue
+		}
+		if _, err := authorizeSet(ctx, s.setRepo, userID, setID); err != nil {
+			return model.StudySession{}, err
+		}
+		unique = append(unique, setID)
+	}
+
+	session := model.StudySession{UserID: userID, SetIDs: unique}
+	if err := s.sessionRepo.Create(ctx, &session); err != nil {
+		return model.StudySession{}, err
+	}
+	return session, nil
+}
+
+func (s *studyService) GetSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (model.StudySession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.StudySession{}, ErrNotFound
+	}
+	if err != nil {
+		return model.StudySession{}, err
+	}
+	if err := AuthorizeUser(userID, session.UserID); err != nil {
+		return model.StudySession{}, err
+	}
+	return session, nil
+}
+
+func (s *studyService) RecordAnswer(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, req model.SessionAnswerRequest) (model.CardReview, error) {
+	session, err := s.GetSession(ctx, userID, sessionID)
+	if err != nil {
+		return model.CardReview{}, err
+	}
+	if session.EndedAt != nil {
+		return model.CardReview{}, ErrSessionClosed
+	}
+
+	// Só aceita cards dos sets que fazem parte da sessão
+	card, err := s.flashcardRepo.GetByID(ctx, req.FlashcardID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !slices.Contains(session.SetIDs, card.FlashcardSetID)) {
+		return model.CardReview{}, ErrNotFound
+	}
+	if err != nil {
+		return model.CardReview{}, err
+	}
+
+	answer := model.ReviewAnswer{
+		FlashcardID:    req.FlashcardID,
+		Grade:          *req.Grade,
+		ResponseTimeMs: req.ResponseTimeMs,
+		SessionID:      &session.ID,
+		NotBefore:      session.StartedAt,
+	}
+	if req.AnsweredAt != nil {
+		answer.AnsweredAt = *req.AnsweredAt
+	}
+	return s.reviewService.Review(ctx, userID, answer)
+}
+
+func (s *studyService) FinishSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (model.StudySession, model.SessionSummary, error) {
+	session, err := s.GetSession(ctx, userID, sessionID)
+	if err != nil {
+		return model.StudySession{}, model.SessionSummary{}, err
+	}
+	if session.EndedAt != nil {
+		return model.StudySession{}, model.SessionSummary{}, ErrSessionClosed
+	}
+
+	session, err = s.sessionRepo.Finish(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Encerrada por outra requisição entre a leitura e a atualização
+		return model.StudySession{}, model.SessionSummary{}, ErrSessionClosed
+	}
+	if err != nil {
+		return model.StudySession{}, model.SessionSummary{}, err
+	}
+
+	return session, summarize(session), nil
+}
+
+func summarize(session model.StudySession) model.SessionSummary {
+	summary := model.SessionSummary{
+		AnswersCount:      session.AnswersCount,
+		CorrectCount:      session.CorrectCount,
+		AverageResponseMs: session.AverageResponseMs,
+	}
+	if session.AnswersCount > 0 {
+		summary.Accuracy = math.Round(float64(session.CorrectCount)/float64(session.AnswersCount)*1000) / 1000
+	}
+	if session.EndedAt != nil {
+		summary.DurationSeconds = int(session.EndedAt.Sub(session.StartedAt).Seconds())
+	}
+	return summary
+}
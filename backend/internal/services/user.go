@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/model"
 	"github.com/fernandocandeiatorres/memoriza-ai/backend/internal/repository"
@@ -11,6 +12,9 @@ import (
 type UserService interface {
 	GetByID(ctx context.Context, userID uuid.UUID) (model.User, error)
 	EnsureUserExists(ctx context.Context, userID uuid.UUID, email string) (model.User, error)
+	// GenerationOptions aplica os padrões às opções de geração e confere a quantidade de cards
+	// contra o plano do usuário (ErrCardLimitExceeded).
+	GenerationOptions(ctx context.Context, userID uuid.UUID, opts model.GenerationOptions) (model.GenerationOptions, error)
 }
 
 type userService struct {
@@ -27,4 +31,17 @@ func (s *userService) GetByID(ctx context.Context, userID uuid.UUID) (model.User
 
 func (s *userService) EnsureUserExists(ctx context.Context, userID uuid.UUID, email string) (model.User, error) {
 	return s.repo.GetOrCreate(ctx, userID, email)
-} 
\ No newline at end of file
+} 
+
+func (s *userService) GenerationOptions(ctx context.Context, userID uuid.UUID, opts model.GenerationOptions) (model.GenerationOptions, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return model.GenerationOptions{}, notFoundIfNoRows(err)
+	}
+
+	opts = opts.WithDefaults()
+	if limit := user.MaxCards(); opts.Count > limit {
+		return model.GenerationOptions{}, fmt.Errorf("%w: plan %q allows up to %d cards per generation", ErrCardLimitExceeded, user.Plan, limit)
+	}
+	return opts, nil
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "..", "../escape", "sources/../../escape", "/etc/passwd"} {
+		if _, err := store.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) should be rejected", key)
+		}
+		if _, err := store.Open(context.Background(), key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) = %v, want an invalid key error", key, err)
+		}
+	}
+
+	// ".." no meio que continua dentro da raiz é aceito
+	if _, err := store.Put(context.Background(), "sources/a/../b", strings.NewReader("x")); err != nil {
+		t.Errorf("key staying inside the root: %v", err)
+	}
+}
+
+func TestLocalStorePutOpenDelete(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	n, err := store.Put(ctx, "sources/u/1", strings.NewReader("conteúdo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len("conteúdo")) {
+		t.Errorf("Put wrote %d bytes, want %d", n, len("conteúdo"))
+	}
+
+	rc, err := store.Open(ctx, "sources/u/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "conteúdo" {
+		t.Errorf("Open returned %q", data)
+	}
+
+	if err := store.Delete(ctx, "sources/u/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(ctx, "sources/u/1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "sources/u/1"); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+}
+
+type failingReader struct{ read bool }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if !r.read {
+		r.read = true
+		return copy(p, "parcial"), nil
+	}
+	return 0, errors.New("connection reset")
+}
+
+func TestLocalStorePutFailureKeepsPreviousObject(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.Put(ctx, "sources/u/1", strings.NewReader("original")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put(ctx, "sources/u/1", &failingReader{}); err == nil {
+		t.Fatal("Put should fail when the reader fails")
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "sources", "u", "1"))
+	if err != nil || string(data) != "original" {
+		t.Errorf("object after failed Put = %q, %v; want the original content", data, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "sources", "u"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %v", entries)
+	}
+}
@@ -0,0 +1,90 @@
+// Package storage guarda os arquivos enviados pelos usuários (material de estudo) num blob store:
+// o disco local em desenvolvimento ou um bucket compatível com S3 (AWS, MinIO, R2, Supabase Storage).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNotFound indica que não há objeto com a chave pedida.
+var ErrNotFound = errors.New("blob not found")
+
+// Store grava e lê objetos por chave. As chaves usam "/" como separador, como no S3.
+type Store interface {
+	// Put grava tudo o que vier de r sob key e devolve quantos bytes foram gravados. Se r falhar
+	// no meio, nada fica gravado.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open abre o objeto para leitura; quem chama fecha o ReadCloser.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete apaga o objeto. Apagar uma chave que não existe não é erro.
+	Delete(ctx context.Context, key string) error
+}
+
+// Tipos de Store aceitos em BLOB_STORE.
+const (
+	KindLocal = "local"
+	KindS3    = "s3"
+)
+
+// DefaultLocalDir é onde o store local grava quando BLOB_DIR não está definido.
+const DefaultLocalDir = "data/blobs"
+
+// Config escolhe e configura o Store.
+type Config struct {
+	Kind string
+	// Dir é a pasta raiz do store local
+	Dir string
+	S3  S3Config
+}
+
+// ConfigFromEnv monta a Config a partir de BLOB_STORE ("local", padrão, ou "s3"), BLOB_DIR e,
+// para o S3, S3_ENDPOINT, S3_BUCKET, S3_REGION, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY e
+// S3_FORCE_PATH_STYLE.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Kind: strings.ToLower(envOr("BLOB_STORE", KindLocal)),
+		Dir:  envOr("BLOB_DIR", DefaultLocalDir),
+		S3: S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			Region:          envOr("S3_REGION", "us-east-1"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			ForcePathStyle:  os.Getenv("S3_FORCE_PATH_STYLE") == "true",
+		},
+	}
+
+	switch cfg.Kind {
+	case KindLocal:
+	case KindS3:
+		if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+			return Config{}, errors.New("S3_ENDPOINT and S3_BUCKET are required when BLOB_STORE=s3")
+		}
+		if cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return Config{}, errors.New("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when BLOB_STORE=s3")
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown BLOB_STORE %q: use %q or %q", cfg.Kind, KindLocal, KindS3)
+	}
+	return cfg, nil
+}
+
+// New cria o Store descrito pela configuração.
+func New(cfg Config) (Store, error) {
+	if cfg.Kind == KindS3 {
+		return NewS3Store(cfg.S3), nil
+	}
+	return NewLocalStore(cfg.Dir)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
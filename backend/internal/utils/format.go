@@ -39,8 +39,8 @@ func StripThinkTagAlternative(response string) string {
 
 // ParseFlashcardsResponse analisa a string JSON e converte os dados para o FlashcardsResponse.
 // Se a resposta for um array, ela itera sobre ele e converte cada objeto,
-// atribuindo os valores de "front" para QuestionText e "back" para AnswerText,
-// além de definir o CardOrder sequencialmente.
+// atribuindo os valores de "front" para QuestionText, "back" para AnswerText e, em
+// transcrições, "timestamp" para SourceTimestamp, além de definir o CardOrder sequencialmente.
 func ParseFlashcardsResponse(jsonStr string) (model.FlashcardsResponse, error) {
 	jsonStr = strings.TrimSpace(jsonStr)
 	
@@ -57,6 +57,10 @@ func ParseFlashcardsResponse(jsonStr string) (model.FlashcardsResponse, error) {
 			QuestionText:   r.Front, // Mapeamento de front para question_text
 			AnswerText:     r.Back,  // Mapeamento de back para answer_text
 		}
+		if r.Timestamp != "" {
+			timestamp := r.Timestamp
+			card.SourceTimestamp = &timestamp
+		}
 		cards = append(cards, card)
 	}
 
@@ -0,0 +1,27 @@
+-- Estado de agendamento (SM-2) de cada flashcard por usuário.
+-- Data: 2026-10-17
+-- Descrição: Guarda fator de facilidade, intervalo, repetições e próxima revisão de cada card
+
+CREATE TABLE IF NOT EXISTS card_reviews (
+    user_id UUID NOT NULL,
+    flashcard_id UUID NOT NULL,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, flashcard_id),
+    CONSTRAINT fk_card_review_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_card_review_flashcard
+      FOREIGN KEY(flashcard_id)
+        REFERENCES flashcards(id)
+        ON DELETE CASCADE
+);
+
+-- Consulta de cards vencidos por usuário
+CREATE INDEX IF NOT EXISTS idx_card_reviews_user_due ON card_reviews (user_id, due_at);
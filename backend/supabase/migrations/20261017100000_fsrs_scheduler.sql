@@ -0,0 +1,44 @@
+-- Suporte ao algoritmo FSRS como alternativa ao SM-2.
+-- Data: 2026-10-17
+-- Descrição: Estado FSRS por card, configurações de estudo por usuário e histórico de revisões
+
+-- 1. Estado FSRS (estabilidade e dificuldade) ao lado do estado SM-2
+ALTER TABLE card_reviews
+ADD COLUMN IF NOT EXISTS stability DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE card_reviews
+ADD COLUMN IF NOT EXISTS difficulty DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- 2. Configurações de estudo do usuário
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY,
+    scheduler TEXT NOT NULL DEFAULT 'sm2' CHECK (scheduler IN ('sm2', 'fsrs')),
+    desired_retention DOUBLE PRECISION NOT NULL DEFAULT 0.9,
+    fsrs_weights DOUBLE PRECISION[],  -- NULL = pesos padrão do FSRS
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user_settings_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 3. Histórico de revisões, usado para otimizar os pesos do FSRS
+CREATE TABLE IF NOT EXISTS review_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    flashcard_id UUID NOT NULL,
+    grade INTEGER NOT NULL CHECK (grade BETWEEN 0 AND 5),
+    scheduler TEXT NOT NULL,
+    reviewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_review_log_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_review_log_flashcard
+      FOREIGN KEY(flashcard_id)
+        REFERENCES flashcards(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_logs_user_reviewed ON review_logs (user_id, reviewed_at);
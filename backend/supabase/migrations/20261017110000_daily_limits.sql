@@ -0,0 +1,9 @@
+-- Limites diários da fila de estudo.
+-- Data: 2026-10-17
+-- Descrição: Quantidade máxima de cards novos e de revisões apresentados por dia
+
+ALTER TABLE user_settings
+ADD COLUMN IF NOT EXISTS new_cards_per_day INTEGER NOT NULL DEFAULT 20 CHECK (new_cards_per_day >= 0);
+
+ALTER TABLE user_settings
+ADD COLUMN IF NOT EXISTS reviews_per_day INTEGER NOT NULL DEFAULT 200 CHECK (reviews_per_day >= 0);
@@ -0,0 +1,59 @@
+-- Sessões de estudo e histórico de revisões somente-inserção.
+-- Data: 2026-10-17
+-- Descrição: Cria study_sessions e completa review_logs com sessão, tempo de resposta e estado do agendador
+
+-- 1. Sessões de estudo
+CREATE TABLE IF NOT EXISTS study_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    set_ids UUID[] NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMPTZ,
+    -- Resumo calculado a partir de review_logs quando a sessão é encerrada
+    answers_count INTEGER NOT NULL DEFAULT 0,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    average_response_ms INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_study_session_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_user ON study_sessions (user_id, started_at DESC);
+
+-- 2. Campos novos do histórico de revisões
+ALTER TABLE review_logs
+ADD COLUMN IF NOT EXISTS session_id UUID REFERENCES study_sessions(id);
+
+ALTER TABLE review_logs
+ADD COLUMN IF NOT EXISTS response_time_ms INTEGER CHECK (response_time_ms >= 0);
+
+ALTER TABLE review_logs
+ADD COLUMN IF NOT EXISTS state_before JSONB;  -- NULL na primeira revisão do card
+
+ALTER TABLE review_logs
+ADD COLUMN IF NOT EXISTS state_after JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_review_logs_session ON review_logs (session_id);
+
+-- 3. O histórico sobrevive à exclusão do card, para recalcular agendas e gerar estatísticas depois
+ALTER TABLE review_logs
+DROP CONSTRAINT IF EXISTS fk_review_log_flashcard;
+
+-- 4. review_logs é somente-inserção: UPDATE e DELETE diretos são rejeitados.
+-- Exclusões em cascata (usuário removido) continuam permitidas.
+CREATE OR REPLACE FUNCTION review_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'review_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_review_logs_append_only ON review_logs;
+CREATE TRIGGER trg_review_logs_append_only
+    BEFORE UPDATE OR DELETE ON review_logs
+    FOR EACH ROW EXECUTE FUNCTION review_logs_append_only();
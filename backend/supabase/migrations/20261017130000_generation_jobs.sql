@@ -0,0 +1,37 @@
+-- Fila de jobs de geração de flashcards.
+-- Data: 2026-10-17
+-- Descrição: Cria generation_jobs, consumida pelos workers com SELECT ... FOR UPDATE SKIP LOCKED
+
+CREATE TABLE IF NOT EXISTS generation_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('prompt')),
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'cancelled')),
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    flashcard_set_id UUID,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- Renovado pelo worker enquanto o job roda; jobs "running" parados há muito tempo voltam para a fila
+    heartbeat_at TIMESTAMPTZ,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_generation_job_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_generation_job_set
+      FOREIGN KEY(flashcard_set_id)
+        REFERENCES flashcard_sets(id)
+        ON DELETE SET NULL
+);
+
+-- Fila: só os jobs que ainda podem ser pegos por um worker
+CREATE INDEX IF NOT EXISTS idx_generation_jobs_queue
+    ON generation_jobs (created_at)
+    WHERE status IN ('queued', 'running');
+
+CREATE INDEX IF NOT EXISTS idx_generation_jobs_user ON generation_jobs (user_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- Plano do usuário.
+-- Data: 2026-10-17
+-- Descrição: Define os limites da geração (por exemplo, quantos cards por pedido)
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS plan TEXT NOT NULL DEFAULT 'free'
+    CHECK (plan IN ('free', 'pro'));
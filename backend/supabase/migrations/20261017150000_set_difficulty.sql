@@ -0,0 +1,9 @@
+-- Nível dos flashcard sets.
+-- Data: 2026-10-17
+-- Descrição: Nível dos cards do set, usado no filtro da listagem. Sets criados à mão podem não ter nível (NULL)
+
+ALTER TABLE flashcard_sets
+ADD COLUMN IF NOT EXISTS difficulty TEXT
+    CHECK (difficulty IN ('beginner', 'intermediate', 'advanced'));
+
+CREATE INDEX IF NOT EXISTS idx_flashcard_sets_user_difficulty ON flashcard_sets (user_id, difficulty);
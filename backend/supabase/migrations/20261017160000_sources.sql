@@ -0,0 +1,20 @@
+-- Arquivos de material de estudo enviados em POST /api/v1/sources.
+-- Data: 2026-10-17
+-- Descrição: Cria sources; o conteúdo fica no blob store (disco local ou S3), aqui só os metadados
+
+CREATE TABLE IF NOT EXISTS sources (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL CHECK (content_type IN ('text', 'pdf', 'image')),
+    mime_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_source_user
+      FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sources_user ON sources (user_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- Novos formatos de material de estudo.
+-- Data: 2026-10-17
+-- Descrição: Aceita DOCX, PPTX, EPUB, HTML e Markdown em sources.content_type
+
+ALTER TABLE sources DROP CONSTRAINT IF EXISTS sources_content_type_check;
+ALTER TABLE sources ADD CONSTRAINT sources_content_type_check
+    CHECK (content_type IN ('text', 'pdf', 'image', 'docx', 'pptx', 'epub', 'html', 'markdown'));
@@ -0,0 +1,10 @@
+-- Transcrições de aulas gravadas.
+-- Data: 2026-10-17
+-- Descrição: Aceita legendas SRT e WebVTT em sources.content_type e guarda nos cards o momento
+-- da aula de onde foram gerados (HH:MM:SS)
+
+ALTER TABLE sources DROP CONSTRAINT IF EXISTS sources_content_type_check;
+ALTER TABLE sources ADD CONSTRAINT sources_content_type_check
+    CHECK (content_type IN ('text', 'pdf', 'image', 'docx', 'pptx', 'epub', 'html', 'markdown', 'srt', 'vtt'));
+
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS source_timestamp TEXT;
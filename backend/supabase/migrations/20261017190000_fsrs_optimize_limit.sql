@@ -0,0 +1,6 @@
+-- Limite de frequência da otimização do FSRS.
+-- Data: 2026-10-17
+-- Descrição: Guarda quando os pesos do FSRS do usuário foram otimizados pela última vez
+
+ALTER TABLE user_settings
+ADD COLUMN IF NOT EXISTS fsrs_optimized_at TIMESTAMPTZ;
@@ -122,7 +122,8 @@ export async function generateFlashcards(
 ): Promise<GenerateFlashcardsResponse> {
   const goRequest = adaptFrontendToGoRequest(request);
 
-  return makeGoBackendRequest<GenerateFlashcardsResponse>(
+  // A geração roda em segundo plano: o backend responde 202 com o ID do job
+  const { job_id } = await makeGoBackendRequest<{ job_id: string }>(
     "/flashcards/generate",
     authToken,
     {
@@ -130,6 +131,53 @@ export async function generateFlashcards(
       body: JSON.stringify(goRequest),
     }
   );
+
+  const job = await waitForJob(job_id, authToken);
+  const flashcards = await getFlashcardsBySetId(job.flashcard_set_id!, authToken);
+
+  return {
+    flashcard_set_id: job.flashcard_set_id!,
+    flashcards,
+  };
+}
+
+interface GenerationJob {
+  id: string;
+  status: "queued" | "running" | "succeeded" | "failed" | "cancelled";
+  progress: number;
+  flashcard_set_id: string | null;
+  error: string | null;
+}
+
+const JOB_POLL_INTERVAL_MS = 1500;
+
+/**
+ * Consulta o status de um job de geração até ele terminar
+ * @param jobId - ID do job retornado por /flashcards/generate
+ * @param authToken - Token de autenticação do usuário
+ * @returns O job concluído com o ID do conjunto criado
+ * @throws Erro se o job falhar ou for cancelado
+ */
+export async function waitForJob(
+  jobId: string,
+  authToken: string
+): Promise<GenerationJob> {
+  for (;;) {
+    const { job } = await makeGoBackendRequest<{ job: GenerationJob }>(
+      `/jobs/${jobId}`,
+      authToken,
+      { method: "GET" }
+    );
+
+    if (job.status === "succeeded") {
+      return job;
+    }
+    if (job.status === "failed" || job.status === "cancelled") {
+      throw new Error(job.error || `Generation job ${job.status}`);
+    }
+
+    await new Promise((resolve) => setTimeout(resolve, JOB_POLL_INTERVAL_MS));
+  }
 }
 
 /**
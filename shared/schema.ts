@@ -82,14 +82,29 @@ export interface GenerateFlashcardsResponse {
   flashcards: GoFlashcard[]; // Lista de flashcards gerados
 }
 
+// Tipos de pergunta aceitos pelo backend Go em question_types
+export type GoQuestionType =
+  | "definition"
+  | "mechanism"
+  | "clinical_vignette"
+  | "differential_diagnosis"
+  | "pharmacology";
+
+// Opções comuns aos pedidos de geração no backend Go
+export interface GoGenerationOptions {
+  count?: number; // Quantidade de cards (padrão 10; o teto depende do plano)
+  question_types?: GoQuestionType[]; // Tipos de pergunta (padrão: variados)
+  language?: "pt-BR" | "en" | "es"; // Idioma dos cards (padrão pt-BR)
+}
+
 // Tipo do request para geração de flashcards no backend Go
-export interface GoGenerateFlashcardsRequest {
+export interface GoGenerateFlashcardsRequest extends GoGenerationOptions {
   prompt: string; // Tópico para geração (equivalente ao 'topic')
   level?: string; // Nível de dificuldade para os flashcards (renamed from 'difficulty')
 }
 
 // Tipo do request para geração de flashcards a partir de resumo no backend Go
-export interface GoGenerateFromSummaryRequest {
+export interface GoGenerateFromSummaryRequest extends GoGenerationOptions {
   content: string; // Conteúdo do resumo (texto, base64 de PDF/imagem)
   content_type: "text" | "pdf" | "image"; // Tipo do conteúdo
   level?: string; // Nível de dificuldade